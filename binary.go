@@ -0,0 +1,70 @@
+package optional
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Presence bytes written by AppendBinary.
+const (
+	binaryEmpty   byte = 0
+	binaryPresent byte = 1
+)
+
+// errBinaryTruncated is returned by ConsumeBinary when src ends before the
+// presence byte.
+var errBinaryTruncated = errors.New("optional: truncated binary Optional")
+
+// AppendBinary appends the binary encoding of o to dst and returns the
+// extended slice: a presence byte, 0 for empty and 1 for present, followed for
+// a present Optional by whatever appendVal appends for the value. Unlike
+// encoding.BinaryMarshaler, it allocates only when dst needs to grow.
+//
+// Example:
+//
+//	buf = optional.AppendBinary(buf, rec.Score, binary.LittleEndian.AppendUint32)
+func AppendBinary[T any](dst []byte, o Optional[T], appendVal func([]byte, T) []byte) []byte {
+	if !o.present {
+		return append(dst, binaryEmpty)
+	}
+	return appendVal(append(dst, binaryPresent), o.value)
+}
+
+// ConsumeBinary decodes an Optional written by AppendBinary from the start of
+// src and returns it with the number of bytes consumed. For a present value,
+// parseVal is called with the bytes after the presence byte and must return
+// the value and the number of bytes it consumed.
+//
+// ConsumeBinary returns an error, and never panics, if src is empty, the
+// presence byte is neither 0 nor 1, parseVal fails, or parseVal reports more
+// bytes consumed than it was given.
+//
+// Example:
+//
+//	score, n, err := optional.ConsumeBinary(buf, func(b []byte) (uint32, int, error) {
+//	    if len(b) < 4 {
+//	        return 0, 0, io.ErrUnexpectedEOF
+//	    }
+//	    return binary.LittleEndian.Uint32(b), 4, nil
+//	})
+//	buf = buf[n:]
+func ConsumeBinary[T any](src []byte, parseVal func([]byte) (T, int, error)) (Optional[T], int, error) {
+	if len(src) == 0 {
+		return Empty[T](), 0, errBinaryTruncated
+	}
+	switch src[0] {
+	case binaryEmpty:
+		return Empty[T](), 1, nil
+	case binaryPresent:
+	default:
+		return Empty[T](), 0, fmt.Errorf("optional: invalid presence byte %#x", src[0])
+	}
+	val, n, err := parseVal(src[1:])
+	if err != nil {
+		return Empty[T](), 0, err
+	}
+	if n < 0 || n > len(src)-1 {
+		return Empty[T](), 0, fmt.Errorf("optional: value parser consumed %d of %d bytes", n, len(src)-1)
+	}
+	return Of(val), 1 + n, nil
+}
@@ -0,0 +1,178 @@
+package optional
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+// The helpers below avoid binary.AppendUvarint and AppendUint32, which need
+// Go 1.19.
+
+func appendString(dst []byte, s string) []byte {
+	var n [binary.MaxVarintLen64]byte
+	dst = append(dst, n[:binary.PutUvarint(n[:], uint64(len(s)))]...)
+	return append(dst, s...)
+}
+
+func parseString(b []byte) (string, int, error) {
+	n, size := binary.Uvarint(b)
+	if size <= 0 || n > uint64(len(b)-size) {
+		return "", 0, io.ErrUnexpectedEOF
+	}
+	return string(b[size : size+int(n)]), size + int(n), nil
+}
+
+func appendUint32(dst []byte, v uint32) []byte {
+	return append(dst, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func parseUint32(b []byte) (uint32, int, error) {
+	if len(b) < 4 {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	return binary.LittleEndian.Uint32(b), 4, nil
+}
+
+func TestConsumeBinaryErrors(t *testing.T) {
+	overconsume := func(b []byte) (uint32, int, error) { return 1, len(b) + 1, nil }
+	negative := func([]byte) (uint32, int, error) { return 1, -1, nil }
+	tests := []struct {
+		name  string
+		src   []byte
+		parse func([]byte) (uint32, int, error)
+		is    error
+	}{
+		{"empty input", nil, parseUint32, errBinaryTruncated},
+		{"invalid presence byte", []byte{2}, parseUint32, nil},
+		{"truncated value", []byte{1, 0, 0}, parseUint32, io.ErrUnexpectedEOF},
+		{"presence byte only", []byte{1}, parseUint32, io.ErrUnexpectedEOF},
+		{"parser overconsumes", []byte{1, 0}, overconsume, nil},
+		{"parser negative count", []byte{1, 0}, negative, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, n, err := ConsumeBinary(tt.src, tt.parse)
+			if err == nil || o.IsPresent() || n != 0 {
+				t.Fatalf("ConsumeBinary = %v, %d, %v, want an error", o, n, err)
+			}
+			if tt.is != nil && !errors.Is(err, tt.is) {
+				t.Errorf("error = %v, want %v", err, tt.is)
+			}
+		})
+	}
+}
+
+func TestBinarySequence(t *testing.T) {
+	in := []Optional[string]{Of("a"), Empty[string](), Of(""), Of("hello")}
+	var buf []byte
+	for _, o := range in {
+		buf = AppendBinary(buf, o, appendString)
+	}
+	if want := []byte{1, 1, 'a', 0, 1, 0, 1, 5, 'h', 'e', 'l', 'l', 'o'}; !bytes.Equal(buf, want) {
+		t.Fatalf("encoded %v, want %v", buf, want)
+	}
+	for i, want := range in {
+		got, n, err := ConsumeBinary(buf, parseString)
+		if err != nil || got != want {
+			t.Fatalf("record %d: ConsumeBinary = %v, %v, want %v", i, got, err, want)
+		}
+		buf = buf[n:]
+	}
+	if len(buf) != 0 {
+		t.Errorf("%d bytes left over", len(buf))
+	}
+}
+
+func FuzzBinaryRoundTrip(f *testing.F) {
+	f.Add(true, "")
+	f.Add(true, "hello")
+	f.Add(false, "")
+	f.Add(true, string(make([]byte, 300)))
+	f.Fuzz(func(t *testing.T, present bool, s string) {
+		o := Empty[string]()
+		if present {
+			o = Of(s)
+		}
+		buf := AppendBinary([]byte("prefix"), o, appendString)[len("prefix"):]
+		got, n, err := ConsumeBinary(buf, parseString)
+		if err != nil || got != o || n != len(buf) {
+			t.Fatalf("ConsumeBinary(AppendBinary(%v)) = %v, %d, %v, want %d bytes", o, got, n, err, len(buf))
+		}
+		// Every truncation of the encoding must be rejected.
+		for i := 0; i < len(buf); i++ {
+			if got, n, err := ConsumeBinary(buf[:i], parseString); err == nil {
+				t.Fatalf("ConsumeBinary(%v) of %d/%d bytes = %v, %d, want an error", o, i, len(buf), got, n)
+			}
+		}
+	})
+}
+
+// Arbitrary input must never panic, and whatever decodes must encode back to
+// the exact bytes consumed.
+func FuzzConsumeBinary(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0})
+	f.Add([]byte{1, 1, 2, 3, 4})
+	f.Add([]byte{1, 1, 2})
+	f.Add([]byte{7})
+	f.Fuzz(func(t *testing.T, src []byte) {
+		for len(src) > 0 {
+			o, n, err := ConsumeBinary(src, parseUint32)
+			if err != nil {
+				if n != 0 || o.IsPresent() {
+					t.Fatalf("ConsumeBinary failed with %v, %d, %v", o, n, err)
+				}
+				return
+			}
+			if n <= 0 || n > len(src) {
+				t.Fatalf("ConsumeBinary consumed %d of %d bytes", n, len(src))
+			}
+			if again := AppendBinary(nil, o, appendUint32); !bytes.Equal(again, src[:n]) {
+				t.Fatalf("re-encoded %v as %v, decoded from %v", o, again, src[:n])
+			}
+			src = src[n:]
+		}
+	})
+}
+
+func BenchmarkAppendBinary(b *testing.B) {
+	buf := make([]byte, 0, 64)
+	present, empty := Of(uint32(42)), Empty[uint32]()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = AppendBinary(buf[:0], present, appendUint32)
+		buf = AppendBinary(buf, empty, appendUint32)
+	}
+}
+
+func BenchmarkConsumeBinary(b *testing.B) {
+	buf := AppendBinary(nil, Of(uint32(42)), appendUint32)
+	buf = AppendBinary(buf, Empty[uint32](), appendUint32)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		o, n, err := ConsumeBinary(buf, parseUint32)
+		if err != nil {
+			b.Fatal(err)
+		}
+		o, _, err = ConsumeBinary(buf[n:], parseUint32)
+		if err != nil || o.IsPresent() {
+			b.Fatal(o, err)
+		}
+	}
+}
+
+func TestBinaryDoesNotAllocate(t *testing.T) {
+	buf := make([]byte, 0, 16)
+	o := Of(uint32(42))
+	if allocs := testing.AllocsPerRun(100, func() {
+		buf = AppendBinary(buf[:0], o, appendUint32)
+		if _, _, err := ConsumeBinary(buf, parseUint32); err != nil {
+			t.Fatal(err)
+		}
+	}); allocs != 0 {
+		t.Errorf("AppendBinary and ConsumeBinary allocate %v times, want 0", allocs)
+	}
+}
@@ -0,0 +1,388 @@
+// Command optionalgen generates typed patch structs for Go structs, as a
+// code-generated alternative to the reflection-based optpatch package.
+//
+// A struct is selected by an "//optionalgen:patch" line in its doc comment:
+//
+//	//optionalgen:patch
+//	type User struct {
+//	    Name  string `json:"name"`
+//	    Email string `json:"email"`
+//	}
+//
+// In a grouped declaration, the line may be placed on the doc comment of a
+// single type spec to select only that spec, or on the doc comment of the
+// whole type ( ... ) group to select every spec in the group, each of which
+// must then be a struct:
+//
+//	//optionalgen:patch
+//	type (
+//	    User struct{ Name string }
+//	    Team struct{ Name string }
+//	)
+//
+// For every selected struct Xxx, optionalgen emits:
+//
+//   - a XxxPatch struct with an optional.Optional field for each field of Xxx
+//   - an Apply(*Xxx) method assigning the present fields
+//   - a FieldMask() []string method listing the paths of the present fields
+//   - nil-safe typed getters GetField() on *XxxPatch
+//
+// Fields are handled as follows:
+//
+//   - unexported fields are skipped, as are fields tagged `optionalgen:"-"`
+//   - the json tag name of a field is copied to its patch field, without
+//     options such as omitempty; a json tag of "-" is copied as is. The tags
+//     name the keys for optjson.Marshal and optpatch.MergePatch, which encode
+//     patch structs with empty fields omitted; encoding/json itself cannot
+//     encode or decode Optional fields
+//   - the field mask path is the json tag name, or the snake_case field name
+//     if there is no json tag name
+//   - an embedded field is patched as a whole, under the name of its type
+//
+// Usage:
+//
+//	//go:generate go run github.com/sergei-bronnikov/go-optional/cmd/optionalgen
+//
+// By default, optionalgen processes the package in the current directory and
+// writes optional_gen.go next to it. Output is gofmt-formatted and
+// deterministic: structs appear in source order.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	directive     = "//optionalgen:patch"
+	optionalPath  = "github.com/sergei-bronnikov/go-optional"
+	defaultOutput = "optional_gen.go"
+)
+
+func main() {
+	output := flag.String("output", defaultOutput, "output file name, relative to the package directory")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: optionalgen [-output file] [package]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	pattern := "."
+	if flag.NArg() > 0 {
+		pattern = flag.Arg(0)
+	}
+	if err := run(pattern, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "optionalgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(pattern, output string) error {
+	pkgs, err := load(pattern, output)
+	if err != nil {
+		return err
+	}
+	for _, pkg := range pkgs {
+		src, err := Generate(pkg, output)
+		if err != nil {
+			return err
+		}
+		if src == nil {
+			continue
+		}
+		dir := filepath.Dir(pkg.GoFiles[0])
+		if err := os.WriteFile(filepath.Join(dir, output), src, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// load loads and type-checks the packages matching pattern. Errors located in
+// a previously generated file named output are ignored: that file may be
+// stale, for example referring to a field that has since been removed, and
+// it is about to be replaced. Its declarations stay visible to the rest of
+// the package, so code using the generated types still type-checks.
+func load(pattern, output string) ([]*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+	}
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, err
+	}
+	failed := false
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, e := range pkg.Errors {
+			if inOutputFile(e, output) {
+				continue
+			}
+			fmt.Fprintln(os.Stderr, e)
+			failed = true
+		}
+	})
+	if failed {
+		return nil, fmt.Errorf("failed to load %s", pattern)
+	}
+	return pkgs, nil
+}
+
+// inOutputFile reports whether e is located in a file named output.
+func inOutputFile(e packages.Error, output string) bool {
+	file := e.Pos
+	if i := strings.LastIndex(file, ".go:"); i >= 0 {
+		file = file[:i+len(".go")]
+	}
+	return file != "" && filepath.Base(file) == output
+}
+
+// patchField describes one field of a generated patch struct.
+type patchField struct {
+	Name     string
+	Type     string
+	JSONTag  string
+	MaskPath string
+}
+
+// patchStruct describes one generated patch struct.
+type patchStruct struct {
+	Name   string
+	Fields []patchField
+}
+
+// Generate returns the generated source for pkg, or nil if the package has no
+// annotated structs. Declarations in the file named output are ignored, so
+// regenerating is idempotent; pkg should be loaded as by load, which also
+// tolerates type errors in that file.
+func Generate(pkg *packages.Package, output string) ([]byte, error) {
+	imports := newImportSet(pkg.Types)
+	var structs []patchStruct
+	for i, file := range pkg.Syntax {
+		if filepath.Base(pkg.CompiledGoFiles[i]) == output {
+			continue
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok || !hasDirective(gen.Doc, ts.Doc) {
+					continue
+				}
+				obj := pkg.TypesInfo.Defs[ts.Name]
+				st, ok := obj.Type().Underlying().(*types.Struct)
+				if !ok {
+					return nil, fmt.Errorf("%s: %s is not a struct", pkg.Fset.Position(ts.Pos()), ts.Name.Name)
+				}
+				if ts.TypeParams != nil && len(ts.TypeParams.List) > 0 {
+					return nil, fmt.Errorf("%s: generic type %s is not supported", pkg.Fset.Position(ts.Pos()), ts.Name.Name)
+				}
+				patch := buildPatch(ts.Name.Name, st, imports)
+				if len(patch.Fields) == 0 {
+					return nil, fmt.Errorf("%s: %s has no exported fields to patch", pkg.Fset.Position(ts.Pos()), ts.Name.Name)
+				}
+				structs = append(structs, patch)
+			}
+		}
+	}
+	if len(structs) == 0 {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by optionalgen. DO NOT EDIT.\n\npackage %s\n\n", pkg.Name)
+	buf.WriteString("import (\n")
+	specs := imports.sorted()
+	for i, imp := range specs {
+		if i > 0 && isStd(imp.path) != isStd(specs[i-1].path) {
+			buf.WriteString("\n")
+		}
+		if imp.name == path.Base(imp.path) {
+			fmt.Fprintf(&buf, "\t%s\n", strconv.Quote(imp.path))
+		} else {
+			fmt.Fprintf(&buf, "\t%s %s\n", imp.name, strconv.Quote(imp.path))
+		}
+	}
+	buf.WriteString(")\n")
+	for _, s := range structs {
+		writePatch(&buf, s)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+func hasDirective(groups ...*ast.CommentGroup) bool {
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		for _, c := range group.List {
+			if strings.TrimSpace(c.Text) == directive {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func buildPatch(name string, st *types.Struct, imports *importSet) patchStruct {
+	s := patchStruct{Name: name + "Patch"}
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+		if !field.Exported() || tag.Get("optionalgen") == "-" {
+			continue
+		}
+		jsonTag := tag.Get("json")
+		jsonName, _, _ := strings.Cut(jsonTag, ",")
+		if jsonTag == "-" {
+			jsonName = "-"
+		}
+		maskPath := jsonName
+		if maskPath == "" || maskPath == "-" {
+			maskPath = toSnakeCase(field.Name())
+		}
+		s.Fields = append(s.Fields, patchField{
+			Name:     field.Name(),
+			Type:     types.TypeString(field.Type(), imports.qualifier),
+			JSONTag:  jsonName,
+			MaskPath: maskPath,
+		})
+	}
+	return s
+}
+
+func writePatch(buf *bytes.Buffer, s patchStruct) {
+	target := strings.TrimSuffix(s.Name, "Patch")
+	fmt.Fprintf(buf, "\n// %s is a patch for %s: each present field replaces the corresponding field.\n", s.Name, target)
+	fmt.Fprintf(buf, "type %s struct {\n", s.Name)
+	for _, f := range s.Fields {
+		fmt.Fprintf(buf, "\t%s optional.Optional[%s]", f.Name, f.Type)
+		if f.JSONTag != "" {
+			fmt.Fprintf(buf, " `json:%s`", strconv.Quote(f.JSONTag))
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n")
+
+	fmt.Fprintf(buf, "\n// Apply assigns the present fields of p to x.\n")
+	fmt.Fprintf(buf, "func (p %s) Apply(x *%s) {\n", s.Name, target)
+	for _, f := range s.Fields {
+		fmt.Fprintf(buf, "\tif v, ok := p.%s.Get(); ok {\n\t\tx.%s = v\n\t}\n", f.Name, f.Name)
+	}
+	buf.WriteString("}\n")
+
+	fmt.Fprintf(buf, "\n// FieldMask returns the paths of the present fields of p, in declaration order.\n")
+	fmt.Fprintf(buf, "func (p %s) FieldMask() []string {\n\tvar paths []string\n", s.Name)
+	for _, f := range s.Fields {
+		fmt.Fprintf(buf, "\tif p.%s.IsPresent() {\n\t\tpaths = append(paths, %s)\n\t}\n", f.Name, strconv.Quote(f.MaskPath))
+	}
+	buf.WriteString("\treturn paths\n}\n")
+
+	for _, f := range s.Fields {
+		fmt.Fprintf(buf, "\n// Get%s returns the %s field of p, or an empty Optional if p is nil.\n", f.Name, f.Name)
+		fmt.Fprintf(buf, "func (p *%s) Get%s() optional.Optional[%s] {\n", s.Name, f.Name, f.Type)
+		fmt.Fprintf(buf, "\tif p == nil {\n\t\treturn optional.Empty[%s]()\n\t}\n\treturn p.%s\n}\n", f.Type, f.Name)
+	}
+}
+
+// importSet tracks the packages referenced by generated code.
+type importSet struct {
+	self   *types.Package
+	byPath map[string]string
+	names  map[string]bool
+}
+
+type importSpec struct {
+	name string
+	path string
+}
+
+// newImportSet returns an importSet for code generated into self. The optional
+// package is always imported under its own name.
+func newImportSet(self *types.Package) *importSet {
+	s := &importSet{self: self, byPath: map[string]string{}, names: map[string]bool{}}
+	s.addNamed(optionalPath, "optional")
+	return s
+}
+
+// qualifier is a types.Qualifier that records every referenced package.
+func (s *importSet) qualifier(pkg *types.Package) string {
+	if pkg == s.self {
+		return ""
+	}
+	if name, ok := s.byPath[pkg.Path()]; ok {
+		return name
+	}
+	return s.addNamed(pkg.Path(), pkg.Name())
+}
+
+func (s *importSet) addNamed(path, name string) string {
+	unique := name
+	for i := 2; s.names[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	s.byPath[path] = unique
+	s.names[unique] = true
+	return unique
+}
+
+func (s *importSet) sorted() []importSpec {
+	specs := make([]importSpec, 0, len(s.byPath))
+	for path, name := range s.byPath {
+		specs = append(specs, importSpec{name: name, path: path})
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		if isStd(specs[i].path) != isStd(specs[j].path) {
+			return isStd(specs[i].path)
+		}
+		return specs[i].path < specs[j].path
+	})
+	return specs
+}
+
+// isStd reports whether path looks like a standard library import path.
+func isStd(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+// toSnakeCase converts a Go identifier to snake_case, keeping initialisms
+// together: "HTTPServerURL" becomes "http_server_url".
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				prev := runes[i-1]
+				nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+					b.WriteByte('_')
+				}
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// Each case is a package under testdata whose generated code is compared with
+// testdata/<case>/optional_gen.go.golden. The stale case ships an outdated
+// optional_gen.go that no longer type-checks and must still regenerate.
+func TestGenerateGolden(t *testing.T) {
+	for _, name := range []string{"basic", "embedded", "stale"} {
+		t.Run(name, func(t *testing.T) {
+			dir := filepath.Join("testdata", name)
+			got := generate(t, dir)
+			golden := filepath.Join(dir, defaultOutput+".golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("generated code differs from %s; rerun with -update and review the diff\ngot:\n%s", golden, got)
+			}
+			if again := generate(t, dir); !bytes.Equal(again, got) {
+				t.Error("output is not deterministic")
+			}
+		})
+	}
+}
+
+func TestGenerateNoAnnotatedStructs(t *testing.T) {
+	if got := generate(t, filepath.Join("testdata", "none")); got != nil {
+		t.Errorf("Generate = %s, want nil", got)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := map[string]string{
+		"notstruct":  "ID is not a struct",
+		"generic":    "generic type Box is not supported",
+		"unexported": "user has no exported fields to patch",
+	}
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			pkgs, err := load("./"+filepath.Join("testdata", name), defaultOutput)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = Generate(pkgs[0], defaultOutput)
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("Generate error = %v, want one containing %q", err, want)
+			}
+		})
+	}
+}
+
+// Type errors outside the output file still fail the load.
+func TestLoadReportsSourceErrors(t *testing.T) {
+	if _, err := load("./testdata/broken", defaultOutput); err == nil {
+		t.Error("load succeeded on a package with a type error")
+	}
+}
+
+func TestInOutputFile(t *testing.T) {
+	tests := []struct {
+		pos  string
+		want bool
+	}{
+		{"/src/app/optional_gen.go:12:3", true},
+		{"optional_gen.go:1", true},
+		{"/src/app/optional_gen.go", true},
+		{"/src/app/user.go:5:2", false},
+		{"/src/optional_gen.go.d/user.go:5:2", false},
+		{"", false},
+		{"-", false},
+	}
+	for _, tt := range tests {
+		if got := inOutputFile(packages.Error{Pos: tt.pos}, defaultOutput); got != tt.want {
+			t.Errorf("inOutputFile(%q) = %v, want %v", tt.pos, got, tt.want)
+		}
+	}
+}
+
+func generate(t *testing.T, dir string) []byte {
+	t.Helper()
+	pkgs, err := load("./"+dir, defaultOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) != 1 {
+		t.Fatalf("loaded %d packages from %s, want 1", len(pkgs), dir)
+	}
+	src, err := Generate(pkgs[0], defaultOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return src
+}
@@ -0,0 +1,23 @@
+package basic
+
+import "time"
+
+// User is a representative model.
+//
+//optionalgen:patch
+type User struct {
+	ID        int64             `json:"id"`
+	Name      string            `json:"name,omitempty"`
+	Email     *string           `json:"email"`
+	Tags      []string          `json:"tags"`
+	Labels    map[string]string `json:"-"`
+	CreatedAt time.Time
+	HTTPProxy string `json:",omitempty"`
+	Password  string `json:"password" optionalgen:"-"`
+	internal  bool
+}
+
+// Unannotated is not patched.
+type Unannotated struct {
+	Name string
+}
@@ -0,0 +1,6 @@
+package broken
+
+//optionalgen:patch
+type User struct {
+	Name Missing
+}
@@ -0,0 +1,38 @@
+package embedded
+
+import (
+	htmltemplate "html/template"
+	"text/template"
+)
+
+type Base struct {
+	ID int
+}
+
+type (
+	// Page groups declarations; the directive is on the type spec.
+	//
+	//optionalgen:patch
+	Page struct {
+		Base
+		*template.Template
+		Body htmltemplate.HTML `json:"body"`
+	}
+
+	Other struct{}
+)
+
+// The directive on the group doc comment selects every spec in the group.
+//
+//optionalgen:patch
+type (
+	Settings struct {
+		Theme optionalTheme `json:"theme"`
+	}
+
+	Limits struct {
+		MaxItems int `json:"max_items"`
+	}
+)
+
+type optionalTheme string
@@ -0,0 +1,6 @@
+package generic
+
+//optionalgen:patch
+type Box[T any] struct {
+	Value T
+}
@@ -0,0 +1,6 @@
+// Package none has no annotated structs.
+package none
+
+type User struct {
+	Name string
+}
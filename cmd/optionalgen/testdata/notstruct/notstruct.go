@@ -0,0 +1,4 @@
+package notstruct
+
+//optionalgen:patch
+type ID int64
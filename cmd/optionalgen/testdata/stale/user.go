@@ -0,0 +1,12 @@
+package stale
+
+//optionalgen:patch
+type User struct {
+	Name string `json:"name"`
+}
+
+// Rename uses the generated patch type, which must stay visible while the
+// stale generated file is replaced.
+func Rename(u *User, name string) {
+	UserPatch{}.Apply(u)
+}
@@ -0,0 +1,6 @@
+package unexported
+
+//optionalgen:patch
+type user struct {
+	name string
+}
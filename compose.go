@@ -0,0 +1,125 @@
+package optional
+
+// Compose2 chains two Optional-returning functions into one. The resulting
+// function calls f, and calls g with f's result only if it is present; an
+// empty result from either step short-circuits to an empty Optional.
+//
+// Because Go methods cannot introduce new type parameters, a multi-step flow
+// that changes types otherwise reads inside-out. Composing the steps first
+// lets it read top to bottom.
+//
+// Example:
+//
+//	resolve := optional.Compose2(
+//	    parseID,    // func(string) Optional[int]
+//	    lookupUser, // func(int) Optional[User]
+//	)
+//	user := resolve("42")
+func Compose2[A, B, C any](f func(A) Optional[B], g func(B) Optional[C]) func(A) Optional[C] {
+	return func(a A) Optional[C] {
+		b, ok := f(a).Get()
+		if !ok {
+			return Empty[C]()
+		}
+		return g(b)
+	}
+}
+
+// Compose3 chains three Optional-returning functions into one, short-circuiting
+// to an empty Optional at the first step that returns empty. Each step is
+// called at most once, and only if the previous step produced a value.
+//
+// Example:
+//
+//	resolve := optional.Compose3(
+//	    parseID,      // func(string) Optional[int]
+//	    lookupUser,   // func(int) Optional[User]
+//	    validateUser, // func(User) Optional[User]
+//	)
+//	user := resolve(r.URL.Query().Get("id"))
+func Compose3[A, B, C, D any](f func(A) Optional[B], g func(B) Optional[C], h func(C) Optional[D]) func(A) Optional[D] {
+	return Compose2(Compose2(f, g), h)
+}
+
+// Chain2 navigates from o through two Optional-returning accessors, such as
+// the hops of a nested model where each level may be absent. Each accessor is
+// called at most once, and only if the previous hop was present; the first
+// empty hop short-circuits to an empty Optional.
+//
+// Example:
+//
+//	city := optional.Chain2(order.Customer(), Customer.Address, Address.City)
+func Chain2[A, B, C any](o Optional[A], f func(A) Optional[B], g func(B) Optional[C]) Optional[C] {
+	a, ok := o.Get()
+	if !ok {
+		return Empty[C]()
+	}
+	return Compose2(f, g)(a)
+}
+
+// Chain3 is like Chain2, with three accessors.
+//
+// Example:
+//
+//	code := optional.Chain3(order.Customer(), Customer.Address, Address.Country, Country.Code)
+func Chain3[A, B, C, D any](o Optional[A], f func(A) Optional[B], g func(B) Optional[C], h func(C) Optional[D]) Optional[D] {
+	return Chain2(o, f, Compose2(g, h))
+}
+
+// Chain4 is like Chain2, with four accessors.
+//
+// Example:
+//
+//	zip := optional.Chain4(order.Customer(), Customer.Account, Account.Billing, Billing.Address, Address.PostalCode)
+func Chain4[A, B, C, D, E any](o Optional[A], f func(A) Optional[B], g func(B) Optional[C], h func(C) Optional[D], i func(D) Optional[E]) Optional[E] {
+	return Chain2(o, f, Compose3(g, h, i))
+}
+
+// ApplyOpt calls the function held by f with the value held by v. The result
+// is present only when both f and v are present, in which case the function is
+// called exactly once and its result is wrapped with Of. A present but nil
+// function is treated like an empty f and yields an empty Optional rather
+// than panicking.
+//
+// Example:
+//
+//	handler := registry.Lookup("resize") // Optional[func(Image) Image]
+//	resized := optional.ApplyOpt(handler, optional.Of(img))
+func ApplyOpt[T, U any](f Optional[func(T) U], v Optional[T]) Optional[U] {
+	fn, ok := f.Get()
+	if !ok || fn == nil {
+		return Empty[U]()
+	}
+	val, ok := v.Get()
+	if !ok {
+		return Empty[U]()
+	}
+	return Of(fn(val))
+}
+
+// MapOr returns fn applied to the value of o if present, otherwise def. fn is
+// called only when o is present.
+//
+// Example:
+//
+//	name := optional.MapOr(user.Nickname(), "anonymous", strings.ToUpper)
+func MapOr[T, U any](o Optional[T], def U, fn func(T) U) U {
+	if val, ok := o.Get(); ok {
+		return fn(val)
+	}
+	return def
+}
+
+// MapOrElse is like MapOr, but computes the default with defFn, which is
+// called exactly once when o is empty and never when it is present. Use it
+// when the default is expensive to compute.
+//
+// Example:
+//
+//	label := optional.MapOrElse(item.Title(), item.GenerateTitle, strings.TrimSpace)
+func MapOrElse[T, U any](o Optional[T], defFn func() U, fn func(T) U) U {
+	if val, ok := o.Get(); ok {
+		return fn(val)
+	}
+	return defFn()
+}
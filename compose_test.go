@@ -0,0 +1,260 @@
+package optional
+
+import (
+	"strconv"
+	"testing"
+)
+
+// counted wraps fn so that every call increments *calls.
+func counted[A, B any](calls *int, fn func(A) Optional[B]) func(A) Optional[B] {
+	return func(a A) Optional[B] {
+		*calls++
+		return fn(a)
+	}
+}
+
+func present[A, B any](fn func(A) B) func(A) Optional[B] {
+	return func(a A) Optional[B] { return Of(fn(a)) }
+}
+
+func absent[A, B any](A) Optional[B] { return Empty[B]() }
+
+func TestChain(t *testing.T) {
+	double := present(func(n int) int { return n * 2 })
+	format := present(strconv.Itoa)
+	length := present(func(s string) int { return len(s) })
+	tests := []struct {
+		name      string
+		run       func(calls []*int) Optional[int]
+		want      Optional[int]
+		wantCalls []int
+	}{
+		{
+			name: "chain2",
+			run: func(c []*int) Optional[int] {
+				return Chain2(Of(50), counted(c[0], double), counted(c[1], present(func(n int) int { return n + 1 })))
+			},
+			want: Of(101), wantCalls: []int{1, 1},
+		},
+		{
+			name: "chain2 empty source",
+			run: func(c []*int) Optional[int] {
+				return Chain2(Empty[int](), counted(c[0], double), counted(c[1], double))
+			},
+			want: Empty[int](), wantCalls: []int{0, 0},
+		},
+		{
+			name: "chain2 empty first hop",
+			run: func(c []*int) Optional[int] {
+				return Chain2(Of(1), counted(c[0], absent[int, int]), counted(c[1], double))
+			},
+			want: Empty[int](), wantCalls: []int{1, 0},
+		},
+		{
+			name: "chain2 empty last hop",
+			run: func(c []*int) Optional[int] {
+				return Chain2(Of(1), counted(c[0], double), counted(c[1], absent[int, int]))
+			},
+			want: Empty[int](), wantCalls: []int{1, 1},
+		},
+		{
+			name: "chain3",
+			run: func(c []*int) Optional[int] {
+				return Chain3(Of(500), counted(c[0], double), counted(c[1], format), counted(c[2], length))
+			},
+			want: Of(4), wantCalls: []int{1, 1, 1},
+		},
+		{
+			name: "chain3 empty middle hop",
+			run: func(c []*int) Optional[int] {
+				return Chain3(Of(500), counted(c[0], double), counted(c[1], absent[int, string]), counted(c[2], length))
+			},
+			want: Empty[int](), wantCalls: []int{1, 1, 0},
+		},
+		{
+			name: "chain3 empty source",
+			run: func(c []*int) Optional[int] {
+				return Chain3(Empty[int](), counted(c[0], double), counted(c[1], format), counted(c[2], length))
+			},
+			want: Empty[int](), wantCalls: []int{0, 0, 0},
+		},
+		{
+			name: "chain4",
+			run: func(c []*int) Optional[int] {
+				return Chain4(Of(5), counted(c[0], double), counted(c[1], format), counted(c[2], length), counted(c[3], double))
+			},
+			want: Of(4), wantCalls: []int{1, 1, 1, 1},
+		},
+		{
+			name: "chain4 empty second hop",
+			run: func(c []*int) Optional[int] {
+				return Chain4(Of(5), counted(c[0], double), counted(c[1], absent[int, string]), counted(c[2], length), counted(c[3], double))
+			},
+			want: Empty[int](), wantCalls: []int{1, 1, 0, 0},
+		},
+		{
+			name: "chain4 empty third hop",
+			run: func(c []*int) Optional[int] {
+				return Chain4(Of(5), counted(c[0], double), counted(c[1], format), counted(c[2], absent[string, int]), counted(c[3], double))
+			},
+			want: Empty[int](), wantCalls: []int{1, 1, 1, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := make([]*int, len(tt.wantCalls))
+			for i := range calls {
+				calls[i] = new(int)
+			}
+			if got := tt.run(calls); got != tt.want {
+				t.Errorf("result = %v, want %v", got, tt.want)
+			}
+			for i, want := range tt.wantCalls {
+				if *calls[i] != want {
+					t.Errorf("accessor %d called %d times, want %d", i+1, *calls[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestMapOr(t *testing.T) {
+	var calls int
+	fn := func(n int) string {
+		calls++
+		return strconv.Itoa(n)
+	}
+	if got := MapOr(Of(7), "none", fn); got != "7" || calls != 1 {
+		t.Errorf("MapOr on present = %q after %d calls, want \"7\" after 1", got, calls)
+	}
+	calls = 0
+	if got := MapOr(Empty[int](), "none", fn); got != "none" || calls != 0 {
+		t.Errorf("MapOr on empty = %q after %d calls, want \"none\" after 0", got, calls)
+	}
+}
+
+func TestMapOrElse(t *testing.T) {
+	tests := []struct {
+		name                string
+		in                  Optional[int]
+		want                string
+		wantFn, wantDefault int
+	}{
+		{"present", Of(7), "7", 1, 0},
+		{"present zero", Of(0), "0", 1, 0},
+		{"empty", Empty[int](), "default", 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fnCalls, defCalls int
+			got := MapOrElse(tt.in,
+				func() string {
+					defCalls++
+					return "default"
+				},
+				func(n int) string {
+					fnCalls++
+					return strconv.Itoa(n)
+				})
+			if got != tt.want {
+				t.Errorf("MapOrElse = %q, want %q", got, tt.want)
+			}
+			if fnCalls != tt.wantFn || defCalls != tt.wantDefault {
+				t.Errorf("fn called %d times and defFn %d times, want %d and %d", fnCalls, defCalls, tt.wantFn, tt.wantDefault)
+			}
+		})
+	}
+}
+
+func TestCompose(t *testing.T) {
+	parseID := func(s string) Optional[int] {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return Empty[int]()
+		}
+		return Of(n)
+	}
+	users := map[int]string{1: "ada", 2: ""}
+	lookup := func(id int) Optional[string] {
+		name, ok := users[id]
+		if !ok {
+			return Empty[string]()
+		}
+		return Of(name)
+	}
+	nonEmpty := func(s string) Optional[string] {
+		if s == "" {
+			return Empty[string]()
+		}
+		return Of(s)
+	}
+	tests := []struct {
+		in        string
+		want      Optional[string]
+		wantCalls [3]int
+	}{
+		{"1", Of("ada"), [3]int{1, 1, 1}},
+		{"2", Empty[string](), [3]int{1, 1, 1}},
+		{"3", Empty[string](), [3]int{1, 1, 0}},
+		{"x", Empty[string](), [3]int{1, 0, 0}},
+	}
+	for _, tt := range tests {
+		var calls [3]int
+		resolve := Compose3(counted(&calls[0], parseID), counted(&calls[1], lookup), counted(&calls[2], nonEmpty))
+		if got := resolve(tt.in); got != tt.want {
+			t.Errorf("Compose3(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+		if calls != tt.wantCalls {
+			t.Errorf("Compose3(%q) step calls = %v, want %v", tt.in, calls, tt.wantCalls)
+		}
+
+		calls = [3]int{}
+		got := Compose2(counted(&calls[0], parseID), counted(&calls[1], lookup))(tt.in)
+		if want := Compose2(parseID, lookup)(tt.in); got != want {
+			t.Errorf("Compose2(%q) = %v, want %v", tt.in, got, want)
+		}
+		if want := [3]int{tt.wantCalls[0], tt.wantCalls[1], 0}; calls != want {
+			t.Errorf("Compose2(%q) step calls = %v, want %v", tt.in, calls, want)
+		}
+	}
+
+	// A composed function can be called repeatedly.
+	resolve := Compose2(parseID, lookup)
+	if resolve("1") != Of("ada") || resolve("1") != Of("ada") {
+		t.Error("Compose2 result not reusable")
+	}
+}
+
+func TestApplyOpt(t *testing.T) {
+	var calls int
+	double := func(n int) int {
+		calls++
+		return n * 2
+	}
+	var nilFn func(int) int
+	tests := []struct {
+		name      string
+		f         Optional[func(int) int]
+		v         Optional[int]
+		want      Optional[int]
+		wantCalls int
+	}{
+		{"both present", Of(double), Of(4), Of(8), 1},
+		{"present zero", Of(double), Of(0), Of(0), 1},
+		{"empty function", Empty[func(int) int](), Of(4), Empty[int](), 0},
+		{"empty value", Of(double), Empty[int](), Empty[int](), 0},
+		{"both empty", Empty[func(int) int](), Empty[int](), Empty[int](), 0},
+		{"nil function", Of(nilFn), Of(4), Empty[int](), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			if got := ApplyOpt(tt.f, tt.v); got != tt.want {
+				t.Errorf("ApplyOpt = %v, want %v", got, tt.want)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("function called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
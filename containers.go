@@ -0,0 +1,115 @@
+package optional
+
+// SafeMap is a map whose accessors return Optionals instead of zero values.
+// Converting a map to a SafeMap and back is free, so it can be adopted with a
+// conversion at an API boundary:
+//
+//	headers := optional.SafeMap[string, string](req.Headers)
+//	auth := headers.Get("Authorization")
+type SafeMap[K comparable, V any] map[K]V
+
+// Get returns the value for k, or an empty Optional if m has no such key. A
+// key mapped to the zero value is present. Get on a nil map returns empty.
+func (m SafeMap[K, V]) Get(k K) Optional[V] {
+	val, ok := m[k]
+	if !ok {
+		return Empty[V]()
+	}
+	return Of(val)
+}
+
+// GetOrInsert returns the value for k, first storing v under k if m has no
+// such key. Like any map write, it panics if m is nil.
+//
+// Example:
+//
+//	counters := optional.SafeMap[string, *Counter]{}
+//	counters.GetOrInsert(name, &Counter{}).Inc()
+func (m SafeMap[K, V]) GetOrInsert(k K, v V) V {
+	if val, ok := m[k]; ok {
+		return val
+	}
+	m[k] = v
+	return v
+}
+
+// Pop removes k from m and returns its value, or an empty Optional if m has
+// no such key.
+func (m SafeMap[K, V]) Pop(k K) Optional[V] {
+	val, ok := m[k]
+	if !ok {
+		return Empty[V]()
+	}
+	delete(m, k)
+	return Of(val)
+}
+
+// SafeSlice is a slice whose accessors return Optionals instead of panicking
+// on out-of-range indexes. Like SafeMap, it converts to and from a plain slice
+// without copying:
+//
+//	args := optional.SafeSlice[string](os.Args[1:])
+//	cmd := args.First()
+type SafeSlice[T any] []T
+
+// At returns the element at index i, or an empty Optional if i is out of
+// range, including negative.
+func (s SafeSlice[T]) At(i int) Optional[T] {
+	if i < 0 || i >= len(s) {
+		return Empty[T]()
+	}
+	return Of(s[i])
+}
+
+// First returns the first element, or an empty Optional if s is empty.
+func (s SafeSlice[T]) First() Optional[T] {
+	return s.At(0)
+}
+
+// Last returns the last element, or an empty Optional if s is empty.
+func (s SafeSlice[T]) Last() Optional[T] {
+	return s.At(len(s) - 1)
+}
+
+// Pop removes the last element from s and returns it, or returns an empty
+// Optional if s is empty. The backing array is not reallocated, but the
+// vacated slot is zeroed so that it does not keep the popped value reachable.
+//
+// Example:
+//
+//	stack := optional.SafeSlice[int]{1, 2}
+//	top := stack.Pop() // Optional[2], stack is now [1]
+func (s *SafeSlice[T]) Pop() Optional[T] {
+	last := s.Last()
+	if last.IsPresent() {
+		var zero T
+		(*s)[len(*s)-1] = zero
+		*s = (*s)[:len(*s)-1]
+	}
+	return last
+}
+
+// CompactMap returns a new map holding the values of the present entries of
+// m, dropping the empty ones. The result is never nil, even for a nil m, so it
+// can be written to directly.
+//
+// Example:
+//
+//	profiles := optional.CompactMap(enriched) // map[string]Optional[Profile] -> map[string]Profile
+func CompactMap[K comparable, V any](m map[K]Optional[V]) map[K]V {
+	dst := make(map[K]V, len(m))
+	CompactMapInto(dst, m)
+	return dst
+}
+
+// CompactMapInto is like CompactMap, but stores the present entries of m in
+// dst, overwriting existing keys and leaving other entries of dst alone. Like
+// any map write, it panics if dst is nil and m has a present entry; a nil dst
+// is fine when there is nothing to store.
+func CompactMapInto[K comparable, V any](dst map[K]V, m map[K]Optional[V]) {
+	for k, o := range m {
+		if o.present {
+			dst[k] = o.value
+		}
+	}
+}
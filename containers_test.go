@@ -0,0 +1,167 @@
+package optional
+
+import "testing"
+
+func TestSafeMap(t *testing.T) {
+	m := SafeMap[string, int]{"zero": 0, "one": 1}
+	for _, tt := range []struct {
+		key  string
+		want Optional[int]
+	}{
+		{"zero", Of(0)},
+		{"one", Of(1)},
+		{"missing", Empty[int]()},
+	} {
+		if got := m.Get(tt.key); got != tt.want {
+			t.Errorf("Get(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+
+	if got := m.GetOrInsert("zero", 5); got != 0 {
+		t.Errorf("GetOrInsert on a stored zero = %d, want 0", got)
+	}
+	if got := m.GetOrInsert("two", 2); got != 2 || m["two"] != 2 {
+		t.Errorf("GetOrInsert(\"two\", 2) = %d, map = %v", got, m)
+	}
+
+	if got := m.Pop("zero"); got != Of(0) {
+		t.Errorf("Pop(\"zero\") = %v, want Optional[0]", got)
+	}
+	if _, ok := m["zero"]; ok {
+		t.Error("Pop did not delete the key")
+	}
+	if got := m.Pop("zero"); got.IsPresent() {
+		t.Errorf("second Pop(\"zero\") = %v, want empty", got)
+	}
+}
+
+func TestSafeMapNil(t *testing.T) {
+	var m SafeMap[string, int]
+	if got := m.Get("k"); got.IsPresent() {
+		t.Errorf("Get on nil map = %v, want empty", got)
+	}
+	if got := m.Pop("k"); got.IsPresent() {
+		t.Errorf("Pop on nil map = %v, want empty", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("GetOrInsert on nil map did not panic")
+		}
+	}()
+	m.GetOrInsert("k", 1)
+}
+
+func TestSafeSlice(t *testing.T) {
+	s := SafeSlice[int]{0, 1, 2}
+	for _, tt := range []struct {
+		i    int
+		want Optional[int]
+	}{
+		{0, Of(0)},
+		{2, Of(2)},
+		{3, Empty[int]()},
+		{-1, Empty[int]()},
+	} {
+		if got := s.At(tt.i); got != tt.want {
+			t.Errorf("At(%d) = %v, want %v", tt.i, got, tt.want)
+		}
+	}
+	if got := s.First(); got != Of(0) {
+		t.Errorf("First() = %v, want Optional[0]", got)
+	}
+	if got := s.Last(); got != Of(2) {
+		t.Errorf("Last() = %v, want Optional[2]", got)
+	}
+
+	for _, s := range []SafeSlice[int]{nil, {}} {
+		if got := s.At(0); got.IsPresent() {
+			t.Errorf("At(0) on %#v = %v, want empty", s, got)
+		}
+		if got := s.First(); got.IsPresent() {
+			t.Errorf("First() on %#v = %v, want empty", s, got)
+		}
+		if got := s.Last(); got.IsPresent() {
+			t.Errorf("Last() on %#v = %v, want empty", s, got)
+		}
+	}
+}
+
+func TestSafeSlicePop(t *testing.T) {
+	s := SafeSlice[int]{0, 1}
+	for _, want := range []Optional[int]{Of(1), Of(0), Empty[int](), Empty[int]()} {
+		if got := s.Pop(); got != want {
+			t.Errorf("Pop() = %v, want %v", got, want)
+		}
+	}
+	if len(s) != 0 {
+		t.Errorf("len after popping everything = %d", len(s))
+	}
+
+	var nilSlice SafeSlice[int]
+	if got := nilSlice.Pop(); got.IsPresent() || nilSlice != nil {
+		t.Errorf("Pop() on nil slice = %v, slice %#v", got, nilSlice)
+	}
+}
+
+func TestSafeSlicePopClearsSlot(t *testing.T) {
+	a, b := new(int), new(int)
+	backing := []*int{a, b}
+	s := SafeSlice[*int](backing)
+	if got := s.Pop(); got != Of(b) {
+		t.Fatalf("Pop() = %v, want Optional[%p]", got, b)
+	}
+	if backing[1] != nil {
+		t.Error("Pop left the popped pointer in the backing array")
+	}
+	if backing[0] != a || &s[0] != &backing[0] {
+		t.Error("Pop reallocated or touched the remaining elements")
+	}
+}
+
+func TestCompactMap(t *testing.T) {
+	m := map[string]Optional[int]{"a": Of(1), "zero": Of(0), "none": Empty[int]()}
+	got := CompactMap(m)
+	if len(got) != 2 || got["a"] != 1 || got["zero"] != 0 {
+		t.Errorf("CompactMap = %v, want map[a:1 zero:0]", got)
+	}
+	if _, ok := got["none"]; ok {
+		t.Error("CompactMap kept an empty entry")
+	}
+
+	for name, in := range map[string]map[string]Optional[int]{
+		"nil":       nil,
+		"empty":     {},
+		"all empty": {"x": Empty[int]()},
+	} {
+		got := CompactMap(in)
+		if got == nil || len(got) != 0 {
+			t.Errorf("CompactMap(%s) = %#v, want an empty non-nil map", name, got)
+		}
+		got["w"] = 1 // must not panic
+	}
+}
+
+func TestCompactMapInto(t *testing.T) {
+	dst := map[string]int{"a": 9, "keep": 5}
+	CompactMapInto(dst, map[string]Optional[int]{"a": Of(1), "keep": Empty[int](), "b": Of(2)})
+	want := map[string]int{"a": 1, "keep": 5, "b": 2}
+	if len(dst) != len(want) {
+		t.Fatalf("CompactMapInto = %v, want %v", dst, want)
+	}
+	for k, v := range want {
+		if dst[k] != v {
+			t.Errorf("dst[%q] = %d, want %d", k, dst[k], v)
+		}
+	}
+
+	// A nil dst is fine as long as nothing needs storing.
+	CompactMapInto(nil, map[string]Optional[int]{"x": Empty[int]()})
+	CompactMapInto[string, int](nil, nil)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("CompactMapInto with a nil dst and a present entry did not panic")
+		}
+	}()
+	CompactMapInto(nil, map[string]Optional[int]{"x": Of(1)})
+}
@@ -0,0 +1,59 @@
+package optional
+
+import "context"
+
+// Key is a typed context key for storing values of type T. Keys are compared by
+// identity, so two keys created with NewKey never collide, even if they share a
+// name. The name is only used for debugging output.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey returns a new unique context key for values of type T.
+//
+// Example:
+//
+//	var userKey = optional.NewKey[User]("user")
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// String returns the name the key was created with.
+func (k *Key[T]) String() string {
+	return "optional.Key[" + k.name + "]"
+}
+
+// IntoContext returns a copy of ctx in which key is associated with v. The
+// value is stored with its presence, so a nil interface or pointer value reads
+// back from FromContext as present.
+//
+// Example:
+//
+//	func authMiddleware(next http.Handler) http.Handler {
+//	    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//	        if user, ok := authenticate(r); ok {
+//	            r = r.WithContext(optional.IntoContext(r.Context(), userKey, user))
+//	        }
+//	        next.ServeHTTP(w, r)
+//	    })
+//	}
+func IntoContext[T any](ctx context.Context, key *Key[T], v T) context.Context {
+	return context.WithValue(ctx, key, Of(v))
+}
+
+// FromContext returns an Optional containing the value associated with key in ctx,
+// or an empty Optional if the key was never set. A value stored with
+// IntoContext is present even if it is nil.
+//
+// Example:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    user := optional.FromContext(r.Context(), userKey)
+//	    if u, ok := user.Get(); ok {
+//	        fmt.Fprintf(w, "hello, %s", u.Name)
+//	    }
+//	}
+func FromContext[T any](ctx context.Context, key *Key[T]) Optional[T] {
+	o, _ := ctx.Value(key).(Optional[T])
+	return o
+}
@@ -0,0 +1,81 @@
+package optional
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestContext(t *testing.T) {
+	userKey := NewKey[string]("user")
+	ctx := context.Background()
+	if got := FromContext(ctx, userKey); got.IsPresent() {
+		t.Errorf("FromContext before IntoContext = %v, want empty", got)
+	}
+
+	ctx = IntoContext(ctx, userKey, "ada")
+	if got := FromContext(ctx, userKey); got != Of("ada") {
+		t.Errorf("FromContext = %v, want Optional[ada]", got)
+	}
+
+	shadowed := IntoContext(ctx, userKey, "bob")
+	if got := FromContext(shadowed, userKey); got != Of("bob") {
+		t.Errorf("FromContext after re-setting = %v, want Optional[bob]", got)
+	}
+	if got := FromContext(ctx, userKey); got != Of("ada") {
+		t.Errorf("re-setting changed the parent context to %v", got)
+	}
+
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if got := FromContext(child, userKey); got != Of("ada") {
+		t.Errorf("FromContext on a derived context = %v, want Optional[ada]", got)
+	}
+}
+
+func TestContextKeysAreDistinct(t *testing.T) {
+	a, b := NewKey[int]("id"), NewKey[int]("id")
+	ctx := IntoContext(context.Background(), a, 1)
+	if got := FromContext(ctx, b); got.IsPresent() {
+		t.Errorf("a key with the same name read %v, want empty", got)
+	}
+	if got := FromContext(ctx, a); got != Of(1) {
+		t.Errorf("FromContext = %v, want Optional[1]", got)
+	}
+
+	// A plain context.WithValue under the key is not an IntoContext value.
+	ctx = context.WithValue(context.Background(), a, 2)
+	if got := FromContext(ctx, a); got.IsPresent() {
+		t.Errorf("value stored without IntoContext read as %v, want empty", got)
+	}
+}
+
+func TestContextNilValue(t *testing.T) {
+	errKey := NewKey[error]("err")
+	ctx := IntoContext(context.Background(), errKey, nil)
+	got := FromContext(ctx, errKey)
+	if !got.IsPresent() {
+		t.Fatal("a stored nil error read back as empty")
+	}
+	if err, _ := got.Get(); err != nil {
+		t.Errorf("stored nil error read back as %v", err)
+	}
+
+	errFailed := errors.New("failed")
+	ctx = IntoContext(ctx, errKey, errFailed)
+	if err, _ := FromContext(ctx, errKey).Get(); err != errFailed {
+		t.Errorf("FromContext = %v, want %v", err, errFailed)
+	}
+
+	ptrKey := NewKey[*int]("ptr")
+	ctx = IntoContext(context.Background(), ptrKey, nil)
+	if p := FromContext(ctx, ptrKey); !p.IsPresent() {
+		t.Error("a stored nil pointer read back as empty")
+	}
+}
+
+func TestKeyString(t *testing.T) {
+	if got := NewKey[int]("request-id").String(); got != "optional.Key[request-id]" {
+		t.Errorf("String() = %q", got)
+	}
+}
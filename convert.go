@@ -0,0 +1,107 @@
+package optional
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// ErrLossyConversion is returned, wrapped, by TryConvert when a value does not
+// fit the destination type.
+var ErrLossyConversion = errors.New("optional: lossy numeric conversion")
+
+// Convert converts the value of o to the numeric type To, returning an empty
+// Optional if the value does not fit To. See TryConvert for the rules.
+//
+// To comes first so that From can be inferred from o.
+//
+// Example:
+//
+//	var id optional.Optional[int64] = row.ID
+//	dto.ID = optional.Convert[int32](id) // empty if the ID overflows int32
+func Convert[To, From Number](o Optional[From]) Optional[To] {
+	c, _ := TryConvert[To](o)
+	return c
+}
+
+// TryConvert is like Convert, but returns an error wrapping ErrLossyConversion
+// when the value does not fit To. An empty o yields an empty Optional and a nil
+// error.
+//
+// A value fits an integer type if it lies within the type's range; a
+// floating-point value must in addition be integral, so NaN, ±Inf and 1.5 never
+// fit. Every integer fits a floating-point type, possibly rounded to the
+// nearest representable value. A float64 fits float32 if its magnitude does
+// not exceed math.MaxFloat32; NaN and ±Inf are carried over as is.
+//
+// Example:
+//
+//	port, err := optional.TryConvert[uint16](cfg.Port)
+//	if err != nil {
+//	    return fmt.Errorf("port: %w", err)
+//	}
+func TryConvert[To, From Number](o Optional[From]) (Optional[To], error) {
+	val, ok := o.Get()
+	if !ok {
+		return Empty[To](), nil
+	}
+	var zero To
+	to := reflect.TypeOf(zero)
+	from := reflect.ValueOf(val)
+	var fits bool
+	switch from.Kind() {
+	case reflect.Float32, reflect.Float64:
+		fits = floatFits(from.Float(), to)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fits = intFits(from.Int(), to)
+	default:
+		fits = uintFits(from.Uint(), to)
+	}
+	if !fits {
+		return Empty[To](), fmt.Errorf("%w: %v does not fit %s", ErrLossyConversion, val, to)
+	}
+	return Of(To(val)), nil
+}
+
+func intFits(i int64, to reflect.Type) bool {
+	switch to.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		min := int64(-1) << (to.Bits() - 1)
+		return i >= min && i <= -(min+1)
+	default:
+		return i >= 0 && uintFits(uint64(i), to)
+	}
+}
+
+func uintFits(u uint64, to reflect.Type) bool {
+	switch to.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return u < uint64(1)<<(to.Bits()-1)
+	default:
+		return to.Bits() == 64 || u < uint64(1)<<to.Bits()
+	}
+}
+
+func floatFits(f float64, to reflect.Type) bool {
+	switch to.Kind() {
+	case reflect.Float32:
+		return math.IsNaN(f) || math.IsInf(f, 0) || math.Abs(f) <= math.MaxFloat32
+	case reflect.Float64:
+		return true
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) || f != math.Trunc(f) {
+		return false
+	}
+	switch to.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		limit := math.Ldexp(1, to.Bits()-1)
+		return f >= -limit && f < limit
+	default:
+		return f >= 0 && f < math.Ldexp(1, to.Bits())
+	}
+}
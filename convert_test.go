@@ -0,0 +1,215 @@
+package optional
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// convCase converts in to To and checks whether it fits.
+type convCase[To, From Number] struct {
+	in   From
+	fits bool
+}
+
+func (c convCase[To, From]) name() string {
+	var to To
+	return fmt.Sprintf("%T(%v) to %T", c.in, c.in, to)
+}
+
+func (c convCase[To, From]) run(t *testing.T) {
+	got, err := TryConvert[To](Of(c.in))
+	if plain := Convert[To](Of(c.in)); !sameConversion(plain, got) {
+		t.Errorf("Convert = %v, TryConvert = %v", plain, got)
+	}
+	if !c.fits {
+		if got.IsPresent() || !errors.Is(err, ErrLossyConversion) {
+			t.Errorf("TryConvert = %v, %v, want empty and ErrLossyConversion", got, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("TryConvert error = %v, want nil", err)
+	}
+	v, ok := got.Get()
+	if want := To(c.in); !ok || (v != want && !(v != v && want != want)) {
+		t.Errorf("TryConvert = %v, want Optional[%v]", got, want)
+	}
+}
+
+// sameConversion reports whether a and b are equal, treating NaN as equal to
+// itself.
+func sameConversion[T Number](a, b Optional[T]) bool {
+	av, aok := a.Get()
+	bv, bok := b.Get()
+	return aok == bok && (av == bv || (av != av && bv != bv))
+}
+
+func TestTryConvert(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	below := func(f float64) float64 { return math.Nextafter(f, 0) }
+	two63, two64 := math.Ldexp(1, 63), math.Ldexp(1, 64)
+
+	tests := []interface {
+		name() string
+		run(*testing.T)
+	}{
+		// signed to narrower signed
+		convCase[int8, int64]{math.MinInt8, true},
+		convCase[int8, int64]{math.MaxInt8, true},
+		convCase[int8, int64]{math.MinInt8 - 1, false},
+		convCase[int8, int64]{math.MaxInt8 + 1, false},
+		convCase[int8, int64]{math.MinInt64, false},
+		convCase[int8, int64]{math.MaxInt64, false},
+		convCase[int16, int64]{math.MinInt16, true},
+		convCase[int16, int64]{math.MaxInt16, true},
+		convCase[int16, int64]{math.MinInt16 - 1, false},
+		convCase[int16, int64]{math.MaxInt16 + 1, false},
+		convCase[int16, int64]{math.MinInt64, false},
+		convCase[int32, int64]{math.MinInt32, true},
+		convCase[int32, int64]{math.MaxInt32, true},
+		convCase[int32, int64]{math.MinInt32 - 1, false},
+		convCase[int32, int64]{math.MaxInt32 + 1, false},
+		convCase[int32, int64]{math.MinInt64, false},
+		convCase[int32, int64]{math.MaxInt64, false},
+		convCase[int, int64]{math.MinInt, true},
+		convCase[int, int64]{math.MaxInt, true},
+		convCase[int64, int64]{math.MinInt64, true},
+		convCase[int64, int8]{math.MinInt8, true},
+
+		// signed to unsigned
+		convCase[uint8, int64]{-1, false},
+		convCase[uint8, int64]{0, true},
+		convCase[uint8, int64]{math.MaxUint8, true},
+		convCase[uint8, int64]{math.MaxUint8 + 1, false},
+		convCase[uint8, int64]{math.MinInt64, false},
+		convCase[uint16, int64]{math.MaxUint16, true},
+		convCase[uint16, int64]{math.MaxUint16 + 1, false},
+		convCase[uint32, int64]{math.MaxUint32, true},
+		convCase[uint32, int64]{math.MaxUint32 + 1, false},
+		convCase[uint32, int32]{-1, false},
+		convCase[uint64, int64]{math.MaxInt64, true},
+		convCase[uint64, int64]{math.MinInt64, false},
+		convCase[uint64, int8]{math.MinInt8, false},
+		convCase[uint, int]{-1, false},
+
+		// unsigned to signed
+		convCase[int8, uint64]{math.MaxInt8, true},
+		convCase[int8, uint64]{math.MaxInt8 + 1, false},
+		convCase[int16, uint64]{math.MaxInt16, true},
+		convCase[int16, uint64]{math.MaxInt16 + 1, false},
+		convCase[int32, uint64]{math.MaxInt32, true},
+		convCase[int32, uint64]{math.MaxInt32 + 1, false},
+		convCase[int64, uint64]{math.MaxInt64, true},
+		convCase[int64, uint64]{math.MaxInt64 + 1, false},
+		convCase[int64, uint64]{math.MaxUint64, false},
+		convCase[int8, uint8]{math.MaxUint8, false},
+
+		// unsigned to narrower unsigned
+		convCase[uint8, uint64]{math.MaxUint8, true},
+		convCase[uint8, uint64]{math.MaxUint8 + 1, false},
+		convCase[uint16, uint64]{math.MaxUint16, true},
+		convCase[uint16, uint64]{math.MaxUint16 + 1, false},
+		convCase[uint32, uint64]{math.MaxUint32, true},
+		convCase[uint32, uint64]{math.MaxUint32 + 1, false},
+		convCase[uint32, uint64]{math.MaxUint64, false},
+		convCase[uint64, uint64]{math.MaxUint64, true},
+
+		// float to signed
+		convCase[int8, float64]{math.MinInt8, true},
+		convCase[int8, float64]{math.MaxInt8, true},
+		convCase[int8, float64]{math.MinInt8 - 1, false},
+		convCase[int8, float64]{math.MaxInt8 + 1, false},
+		convCase[int8, float64]{math.MaxInt8 + 0.5, false},
+		convCase[int8, float64]{math.MinInt8 - 0.5, false},
+		convCase[int8, float64]{1.5, false},
+		convCase[int8, float64]{math.Copysign(0, -1), true},
+		convCase[int8, float64]{nan, false},
+		convCase[int8, float64]{inf, false},
+		convCase[int8, float64]{-inf, false},
+		convCase[int16, float64]{math.MaxInt16, true},
+		convCase[int16, float64]{math.MaxInt16 + 1, false},
+		convCase[int16, float64]{nan, false},
+		convCase[int32, float64]{math.MinInt32, true},
+		convCase[int32, float64]{math.MaxInt32, true},
+		convCase[int32, float64]{math.MinInt32 - 1, false},
+		convCase[int32, float64]{math.MaxInt32 + 1, false},
+		convCase[int32, float64]{inf, false},
+		convCase[int64, float64]{-two63, true},
+		convCase[int64, float64]{below(two63), true},
+		convCase[int64, float64]{two63, false},
+		convCase[int64, float64]{math.MaxInt64, false}, // rounds to 2^63
+		convCase[int64, float64]{math.Nextafter(-two63, -inf), false},
+		convCase[int64, float64]{nan, false},
+		convCase[int64, float64]{inf, false},
+		convCase[int64, float64]{-inf, false},
+		convCase[int8, float32]{math.MaxInt8, true},
+		convCase[int8, float32]{math.MaxInt8 + 1, false},
+		convCase[int8, float32]{float32(nan), false},
+		convCase[int8, float32]{float32(inf), false},
+		convCase[int32, float32]{math.MinInt32, true},
+		convCase[int32, float32]{math.MaxInt32, false}, // rounds to 2^31
+
+		// float to unsigned
+		convCase[uint8, float64]{0, true},
+		convCase[uint8, float64]{math.Copysign(0, -1), true},
+		convCase[uint8, float64]{math.MaxUint8, true},
+		convCase[uint8, float64]{math.MaxUint8 + 1, false},
+		convCase[uint8, float64]{-1, false},
+		convCase[uint8, float64]{-0.5, false},
+		convCase[uint8, float64]{nan, false},
+		convCase[uint8, float64]{inf, false},
+		convCase[uint8, float64]{-inf, false},
+		convCase[uint16, float64]{math.MaxUint16, true},
+		convCase[uint16, float64]{math.MaxUint16 + 1, false},
+		convCase[uint32, float64]{math.MaxUint32, true},
+		convCase[uint32, float64]{math.MaxUint32 + 1, false},
+		convCase[uint64, float64]{below(two64), true},
+		convCase[uint64, float64]{two64, false},
+		convCase[uint64, float64]{math.MaxUint64, false}, // rounds to 2^64
+		convCase[uint64, float64]{-1, false},
+		convCase[uint64, float64]{nan, false},
+		convCase[uint64, float64]{inf, false},
+
+		// float to float
+		convCase[float32, float64]{math.MaxFloat32, true},
+		convCase[float32, float64]{-math.MaxFloat32, true},
+		convCase[float32, float64]{math.Nextafter(math.MaxFloat32, inf), false},
+		convCase[float32, float64]{-math.Nextafter(math.MaxFloat32, inf), false},
+		convCase[float32, float64]{math.MaxFloat64, false},
+		convCase[float32, float64]{math.SmallestNonzeroFloat64, true},
+		convCase[float32, float64]{nan, true},
+		convCase[float32, float64]{inf, true},
+		convCase[float32, float64]{-inf, true},
+		convCase[float64, float32]{math.MaxFloat32, true},
+		convCase[float64, float32]{float32(nan), true},
+		convCase[float64, float32]{float32(-inf), true},
+		convCase[float64, float64]{nan, true},
+
+		// integer to float
+		convCase[float32, int64]{math.MinInt64, true},
+		convCase[float32, int64]{math.MaxInt64, true},
+		convCase[float64, int64]{math.MaxInt64, true},
+		convCase[float32, uint64]{math.MaxUint64, true},
+		convCase[float64, uint64]{math.MaxUint64, true},
+		convCase[float64, int8]{math.MinInt8, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name(), tc.run)
+	}
+}
+
+func TestTryConvertEmpty(t *testing.T) {
+	got, err := TryConvert[int8](Empty[float64]())
+	if got.IsPresent() || err != nil {
+		t.Errorf("TryConvert(empty) = %v, %v, want empty, nil", got, err)
+	}
+}
+
+func TestTryConvertError(t *testing.T) {
+	_, err := TryConvert[uint8](Of(256))
+	if got, want := err.Error(), "optional: lossy numeric conversion: 256 does not fit uint8"; got != want {
+		t.Errorf("error = %q, want %q", got, want)
+	}
+}
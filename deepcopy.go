@@ -0,0 +1,45 @@
+package optional
+
+// DeepCopyInto copies o into out, for use in code generated by controller-gen
+// and deepcopy-gen, which call DeepCopyInto on fields whose type provides it.
+//
+// If *T has a DeepCopyInto(*T) method, as Kubernetes API types and types run
+// through deepcopy-gen do, it is used to copy the value. Otherwise the value
+// is copied by assignment, which is a deep copy only for value types such as
+// numbers, strings and structs of them. For a T holding slices, maps or
+// pointers, declare a named type with its own DeepCopyInto method to supply
+// the deep clone:
+//
+//	type Labels map[string]string
+//
+//	func (in *Labels) DeepCopyInto(out *Labels) {
+//	    *out = make(Labels, len(*in))
+//	    for k, v := range *in {
+//	        (*out)[k] = v
+//	    }
+//	}
+//
+//	type Spec struct {
+//	    Labels optional.Optional[Labels] `json:"labels"`
+//	}
+func (o Optional[T]) DeepCopyInto(out *Optional[T]) {
+	out.present = o.present
+	if !o.present {
+		var zero T
+		out.value = zero
+		return
+	}
+	if c, ok := any(&o.value).(interface{ DeepCopyInto(*T) }); ok {
+		c.DeepCopyInto(&out.value)
+		return
+	}
+	out.value = o.value
+}
+
+// DeepCopy returns a deep copy of o. See DeepCopyInto for how the value is
+// copied.
+func (o Optional[T]) DeepCopy() Optional[T] {
+	var out Optional[T]
+	o.DeepCopyInto(&out)
+	return out
+}
@@ -0,0 +1,141 @@
+package optional
+
+import (
+	"reflect"
+	"testing"
+)
+
+type labels map[string]string
+
+func (in *labels) DeepCopyInto(out *labels) {
+	*out = make(labels, len(*in))
+	for k, v := range *in {
+		(*out)[k] = v
+	}
+}
+
+type ports []int
+
+func (in *ports) DeepCopyInto(out *ports) {
+	*out = append(ports(nil), *in...)
+}
+
+type limits struct {
+	CPU *int
+}
+
+func (in *limits) DeepCopyInto(out *limits) {
+	*out = *in
+	if in.CPU != nil {
+		cpu := *in.CPU
+		out.CPU = &cpu
+	}
+}
+
+type spec struct {
+	Replicas int
+	Name     string
+}
+
+// crdSpec mirrors a CRD spec struct whose generated DeepCopyInto calls
+// DeepCopyInto on each Optional field.
+type crdSpec struct {
+	Labels Optional[labels]
+	Ports  Optional[ports]
+	Limits Optional[limits]
+	Spec   Optional[spec]
+}
+
+func (in *crdSpec) DeepCopyInto(out *crdSpec) {
+	in.Labels.DeepCopyInto(&out.Labels)
+	in.Ports.DeepCopyInto(&out.Ports)
+	in.Limits.DeepCopyInto(&out.Limits)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+func newCRDSpec() crdSpec {
+	cpu := 2
+	return crdSpec{
+		Labels: Of(labels{"app": "web"}),
+		Ports:  Of(ports{80, 443}),
+		Limits: Of(limits{CPU: &cpu}),
+		Spec:   Of(spec{Replicas: 3, Name: "web"}),
+	}
+}
+
+func TestDeepCopyIndependence(t *testing.T) {
+	orig := newCRDSpec()
+	var cp crdSpec
+	orig.DeepCopyInto(&cp)
+	if !reflect.DeepEqual(cp, orig) {
+		t.Fatalf("copy = %+v, want %+v", cp, orig)
+	}
+
+	l, _ := cp.Labels.Get()
+	l["app"] = "api"
+	l["tier"] = "backend"
+	p, _ := cp.Ports.Get()
+	p[0] = 8080
+	lim, _ := cp.Limits.Get()
+	*lim.CPU = 8
+	cp.Spec.Update(func(s *spec) { s.Replicas = 1 })
+
+	if want := newCRDSpec(); !reflect.DeepEqual(orig, want) {
+		t.Errorf("mutating the copy changed the original to %+v, want %+v", orig, want)
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	orig := Of(ports{1, 2})
+	cp := orig.DeepCopy()
+	p, _ := cp.Get()
+	p[0] = 9
+	if got, _ := orig.Get(); got[0] != 1 {
+		t.Errorf("DeepCopy shares the backing array: original is %v", got)
+	}
+
+	if cp := Empty[ports]().DeepCopy(); cp.IsPresent() {
+		t.Errorf("DeepCopy of empty = %v, want empty", cp)
+	}
+}
+
+func TestDeepCopyIntoEmptyClearsOut(t *testing.T) {
+	out := Of(labels{"stale": "value"})
+	Empty[labels]().DeepCopyInto(&out)
+	if out.IsPresent() || out.value != nil {
+		t.Errorf("DeepCopyInto from empty left %#v", out)
+	}
+}
+
+func TestDeepCopyMethodCalled(t *testing.T) {
+	var calls int
+	orig := Of(countingCopy{calls: &calls})
+	orig.DeepCopy()
+	if calls != 1 {
+		t.Errorf("DeepCopyInto of the value called %d times, want 1", calls)
+	}
+	Empty[countingCopy]().DeepCopy()
+	if calls != 1 {
+		t.Errorf("DeepCopyInto called for an empty Optional")
+	}
+}
+
+type countingCopy struct{ calls *int }
+
+func (in *countingCopy) DeepCopyInto(out *countingCopy) {
+	*in.calls++
+	*out = *in
+}
+
+// TestDeepCopyPlainReference pins the documented limitation: without a
+// DeepCopyInto method, a reference-typed value is copied by assignment and
+// the copy aliases the original.
+func TestDeepCopyPlainReference(t *testing.T) {
+	orig := Of([]int{1, 2})
+	cp := orig.DeepCopy()
+	s, _ := cp.Get()
+	s[0] = 9
+	if got, _ := orig.Get(); got[0] != 9 {
+		t.Errorf("plain slice was cloned: original is %v", got)
+	}
+}
@@ -0,0 +1,82 @@
+package optional
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Defaulted is an Optional bundled with a default value, modelling a setting
+// that always has an effective value but may or may not have been configured
+// explicitly. Code reads the effective value with Get, while diagnostics can
+// use IsExplicit to report which settings were actually set.
+//
+// The zero value is unset, with the zero value of T as its default.
+type Defaulted[T any] struct {
+	opt Optional[T]
+	def T
+}
+
+// WithDefault returns an unset Defaulted whose effective value is def.
+//
+// Example:
+//
+//	type Config struct {
+//	    Workers optional.Defaulted[int] `json:"workers"`
+//	}
+//	cfg := Config{Workers: optional.WithDefault(4)}
+//	err := json.Unmarshal(data, &cfg) // Workers is explicit only if "workers" appears
+func WithDefault[T any](def T) Defaulted[T] {
+	return Defaulted[T]{def: def}
+}
+
+// Get returns the explicitly set value, or the default if none was set.
+func (d Defaulted[T]) Get() T {
+	return d.opt.OrElse(d.def)
+}
+
+// Default returns the default value, whether or not a value was set.
+func (d Defaulted[T]) Default() T {
+	return d.def
+}
+
+// IsExplicit reports whether a value was set with Set or by unmarshaling,
+// even if it equals the default.
+func (d Defaulted[T]) IsExplicit() bool {
+	return d.opt.IsPresent()
+}
+
+// Optional returns the explicitly set value as an Optional, empty if unset.
+func (d Defaulted[T]) Optional() Optional[T] {
+	return d.opt
+}
+
+// Set sets the value explicitly.
+func (d *Defaulted[T]) Set(v T) {
+	d.opt = Of(v)
+}
+
+// Reset clears the explicitly set value, so that Get returns the default again.
+func (d *Defaulted[T]) Reset() {
+	d.opt = Empty[T]()
+}
+
+// MarshalJSON implements json.Marshaler by encoding the effective value.
+func (d Defaulted[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Get())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. encoding/json calls it only for
+// keys present in the input, so a missing key leaves d unset with its default
+// intact. A JSON null resets d; any other value sets it explicitly.
+func (d *Defaulted[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		d.Reset()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	d.Set(v)
+	return nil
+}
@@ -0,0 +1,86 @@
+package optional
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// A value explicitly set to the default must still count as explicit.
+func TestDefaultedExplicitDefault(t *testing.T) {
+	d := WithDefault(4)
+	if d.IsExplicit() || d.Get() != 4 {
+		t.Fatalf("WithDefault(4) = explicit %v, value %v, want unset 4", d.IsExplicit(), d.Get())
+	}
+	d.Set(4)
+	if !d.IsExplicit() || d.Get() != 4 || d.Optional() != Of(4) {
+		t.Errorf("after Set(4): explicit %v, value %v, optional %v", d.IsExplicit(), d.Get(), d.Optional())
+	}
+	d.Reset()
+	if d.IsExplicit() || d.Get() != 4 || d.Default() != 4 {
+		t.Errorf("after Reset: explicit %v, value %v, default %v", d.IsExplicit(), d.Get(), d.Default())
+	}
+
+	var zero Defaulted[int]
+	zero.Set(0)
+	if !zero.IsExplicit() {
+		t.Error("zero value Set(0) is not explicit")
+	}
+}
+
+func TestDefaultedUnmarshalJSON(t *testing.T) {
+	type config struct {
+		Workers Defaulted[int] `json:"workers"`
+	}
+	tests := []struct {
+		in           string
+		wantExplicit bool
+		want         int
+	}{
+		{`{}`, false, 4},
+		{`{"workers":4}`, true, 4},
+		{`{"workers":8}`, true, 8},
+		{`{"workers":0}`, true, 0},
+		{`{"workers":null}`, false, 4},
+		{`{"workers": null }`, false, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			cfg := config{Workers: WithDefault(4)}
+			if err := json.Unmarshal([]byte(tt.in), &cfg); err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Workers.IsExplicit() != tt.wantExplicit || cfg.Workers.Get() != tt.want {
+				t.Errorf("explicit %v, value %v, want %v, %v", cfg.Workers.IsExplicit(), cfg.Workers.Get(), tt.wantExplicit, tt.want)
+			}
+			if cfg.Workers.Default() != 4 {
+				t.Errorf("default = %v, want 4", cfg.Workers.Default())
+			}
+		})
+	}
+
+	cfg := config{Workers: WithDefault(4)}
+	cfg.Workers.Set(8)
+	if err := json.Unmarshal([]byte(`{"workers":null}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Workers.IsExplicit() || cfg.Workers.Get() != 4 {
+		t.Errorf("null did not reset: explicit %v, value %v", cfg.Workers.IsExplicit(), cfg.Workers.Get())
+	}
+	if err := json.Unmarshal([]byte(`{"workers":"many"}`), &cfg); err == nil {
+		t.Error("Unmarshal of a string into Defaulted[int] succeeded")
+	}
+}
+
+func TestDefaultedMarshalJSON(t *testing.T) {
+	d := WithDefault("info")
+	for _, want := range []string{`"info"`, `"debug"`} {
+		got, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("Marshal = %s, want %s", got, want)
+		}
+		d.Set("debug")
+	}
+}
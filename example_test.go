@@ -0,0 +1,145 @@
+package optional_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+func parseID(s string) optional.Optional[int] {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return optional.Empty[int]()
+	}
+	return optional.Of(n)
+}
+
+func lookupUser(id int) optional.Optional[string] {
+	users := map[int]string{42: "ada", 7: "  "}
+	name, ok := users[id]
+	if !ok {
+		return optional.Empty[string]()
+	}
+	return optional.Of(name)
+}
+
+func validName(name string) optional.Optional[string] {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return optional.Empty[string]()
+	}
+	return optional.Of(name)
+}
+
+func ExampleCompose2() {
+	resolve := optional.Compose2(parseID, lookupUser)
+	fmt.Println(resolve("42"))
+	fmt.Println(resolve("13"))
+	fmt.Println(resolve("forty-two"))
+	// Output:
+	// Optional[ada]
+	// Optional.empty
+	// Optional.empty
+}
+
+func ExampleCompose3() {
+	resolve := optional.Compose3(parseID, lookupUser, validName)
+	fmt.Println(resolve("42"))
+	fmt.Println(resolve("7"))
+	// Output:
+	// Optional[ada]
+	// Optional.empty
+}
+
+func ExampleApplyOpt() {
+	handlers := map[string]func(string) string{"upper": strings.ToUpper}
+	lookup := func(name string) optional.Optional[func(string) string] {
+		fn, ok := handlers[name]
+		if !ok {
+			return optional.Empty[func(string) string]()
+		}
+		return optional.Of(fn)
+	}
+	fmt.Println(optional.ApplyOpt(lookup("upper"), optional.Of("go")))
+	fmt.Println(optional.ApplyOpt(lookup("lower"), optional.Of("go")))
+	fmt.Println(optional.ApplyOpt(lookup("upper"), optional.Empty[string]()))
+	// Output:
+	// Optional[GO]
+	// Optional.empty
+	// Optional.empty
+}
+
+func ExampleWhen() {
+	includeLimit := true
+	fmt.Println(optional.When(includeLimit, 100))
+	fmt.Println(optional.When(!includeLimit, 100))
+	// Output:
+	// Optional[100]
+	// Optional.empty
+}
+
+func ExampleWhenFunc() {
+	type user struct{ Name string }
+	for _, u := range []*user{{Name: "ada"}, nil} {
+		// The function is not called for the nil user.
+		fmt.Println(optional.WhenFunc(u != nil, func() string { return u.Name }))
+	}
+	// Output:
+	// Optional[ada]
+	// Optional.empty
+}
+
+func ExampleUnless() {
+	page, size := 3, 20
+	fmt.Println(optional.Unless(page == 0, page*size))
+	fmt.Println(optional.Unless(true, page*size))
+	// Output:
+	// Optional[60]
+	// Optional.empty
+}
+
+func ExampleUnlessFunc() {
+	cursors := []string{"a", "b"}
+	done := false
+	fmt.Println(optional.UnlessFunc(done, func() string { return cursors[len(cursors)-1] }))
+	cursors, done = nil, true
+	fmt.Println(optional.UnlessFunc(done, func() string { return cursors[len(cursors)-1] }))
+	// Output:
+	// Optional[b]
+	// Optional.empty
+}
+
+type user struct{ Name string }
+
+var userKey = optional.NewKey[user]("user")
+
+func authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if name := r.Header.Get("X-User"); name != "" {
+			r = r.WithContext(optional.IntoContext(r.Context(), userKey, user{Name: name}))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func ExampleIntoContext() {
+	handler := authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := optional.MapOr(optional.FromContext(r.Context(), userKey), "stranger", func(u user) string { return u.Name })
+		fmt.Fprintf(w, "hello, %s", name)
+	}))
+
+	for _, name := range []string{"ada", ""} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User", name)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		fmt.Println(rec.Body.String())
+	}
+	// Output:
+	// hello, ada
+	// hello, stranger
+}
@@ -0,0 +1,91 @@
+package optional
+
+import (
+	"sync"
+	"time"
+)
+
+// Expiring holds an Optional that becomes empty again at a deadline, such as a
+// cached token or a resolved address. A value expires at the instant of its
+// deadline: it is present only while the clock reads strictly before it.
+//
+// Expiring is safe for concurrent use. The zero value is an empty Expiring that
+// reads time.Now; an Expiring must not be copied after first use.
+//
+// Example:
+//
+//	var token optional.Expiring[string]
+//	token.SetFor(resp.AccessToken, time.Duration(resp.ExpiresIn)*time.Second)
+//	if t, ok := token.Get().Get(); ok {
+//	    req.Header.Set("Authorization", "Bearer "+t)
+//	}
+type Expiring[T any] struct {
+	// Now returns the current time; it defaults to time.Now when nil. Tests
+	// can replace it with a fake clock. It must be set before first use.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	opt       Optional[T]
+	expiresAt time.Time
+}
+
+func (e *Expiring[T]) now() time.Time {
+	if e.Now != nil {
+		return e.Now()
+	}
+	return time.Now()
+}
+
+// Set stores v until expiresAt. A deadline that is not after the current time
+// leaves the Expiring empty.
+func (e *Expiring[T]) Set(v T, expiresAt time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.opt = Of(v)
+	e.expiresAt = expiresAt
+}
+
+// SetFor stores v for ttl from now. A ttl that is not positive leaves the
+// Expiring empty.
+func (e *Expiring[T]) SetFor(v T, ttl time.Duration) {
+	e.Set(v, e.now().Add(ttl))
+}
+
+// Clear makes the Expiring empty before its deadline.
+func (e *Expiring[T]) Clear() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.clearLocked()
+}
+
+func (e *Expiring[T]) clearLocked() {
+	e.opt = Empty[T]()
+	e.expiresAt = time.Time{}
+}
+
+// Get returns the value if one is set and has not expired, otherwise an empty
+// Optional. An expired value is released, so it can be garbage collected.
+func (e *Expiring[T]) Get() Optional[T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.expireLocked()
+	return e.opt
+}
+
+// ExpiresAt returns the deadline of the current value, or an empty Optional if
+// no value is present.
+func (e *Expiring[T]) ExpiresAt() Optional[time.Time] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.expireLocked()
+	if !e.opt.present {
+		return Empty[time.Time]()
+	}
+	return Of(e.expiresAt)
+}
+
+func (e *Expiring[T]) expireLocked() {
+	if e.opt.present && !e.now().Before(e.expiresAt) {
+		e.clearLocked()
+	}
+}
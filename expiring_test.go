@@ -0,0 +1,138 @@
+package optional
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock that only moves when told to.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newExpiring(start time.Time) (*Expiring[string], *fakeClock) {
+	clock := &fakeClock{now: start}
+	return &Expiring[string]{Now: clock.Now}, clock
+}
+
+func TestExpiringDeadline(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	e, clock := newExpiring(start)
+	e.SetFor("token", time.Minute)
+	deadline := start.Add(time.Minute)
+
+	if got := e.ExpiresAt(); got != Of(deadline) {
+		t.Errorf("ExpiresAt = %v, want Optional[%v]", got, deadline)
+	}
+	clock.Advance(time.Minute - time.Nanosecond)
+	if got := e.Get(); got != Of("token") {
+		t.Errorf("one nanosecond before the deadline: Get = %v, want Optional[token]", got)
+	}
+	clock.Advance(time.Nanosecond)
+	if got := e.Get(); got.IsPresent() {
+		t.Errorf("at the deadline: Get = %v, want empty", got)
+	}
+	if got := e.ExpiresAt(); got.IsPresent() {
+		t.Errorf("at the deadline: ExpiresAt = %v, want empty", got)
+	}
+	// Moving the clock back does not revive an expired value.
+	clock.Advance(-time.Hour)
+	if got := e.Get(); got.IsPresent() {
+		t.Errorf("after expiry: Get = %v, want empty", got)
+	}
+}
+
+func TestExpiringSet(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		set  func(*Expiring[string])
+		want Optional[string]
+	}{
+		{"future deadline", func(e *Expiring[string]) { e.Set("v", start.Add(time.Nanosecond)) }, Of("v")},
+		{"deadline now", func(e *Expiring[string]) { e.Set("v", start) }, Empty[string]()},
+		{"past deadline", func(e *Expiring[string]) { e.Set("v", start.Add(-time.Second)) }, Empty[string]()},
+		{"zero deadline", func(e *Expiring[string]) { e.Set("v", time.Time{}) }, Empty[string]()},
+		{"positive ttl", func(e *Expiring[string]) { e.SetFor("v", time.Nanosecond) }, Of("v")},
+		{"zero ttl", func(e *Expiring[string]) { e.SetFor("v", 0) }, Empty[string]()},
+		{"negative ttl", func(e *Expiring[string]) { e.SetFor("v", -time.Second) }, Empty[string]()},
+		{"zero value present", func(e *Expiring[string]) { e.SetFor("", time.Second) }, Of("")},
+		{"overwrite", func(e *Expiring[string]) {
+			e.SetFor("old", time.Second)
+			e.SetFor("new", time.Second)
+		}, Of("new")},
+		{"overwrite with expired", func(e *Expiring[string]) {
+			e.SetFor("old", time.Hour)
+			e.SetFor("new", 0)
+		}, Empty[string]()},
+		{"clear", func(e *Expiring[string]) {
+			e.SetFor("v", time.Hour)
+			e.Clear()
+		}, Empty[string]()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _ := newExpiring(start)
+			tt.set(e)
+			if got := e.Get(); got != tt.want {
+				t.Errorf("Get = %v, want %v", got, tt.want)
+			}
+			if got := e.ExpiresAt(); got.IsPresent() != tt.want.IsPresent() {
+				t.Errorf("ExpiresAt = %v, want present %v", got, tt.want.IsPresent())
+			}
+		})
+	}
+}
+
+func TestExpiringZeroValue(t *testing.T) {
+	var e Expiring[int]
+	if got := e.Get(); got.IsPresent() {
+		t.Errorf("zero value Get = %v, want empty", got)
+	}
+	e.SetFor(1, time.Hour)
+	if got := e.Get(); got != Of(1) {
+		t.Errorf("Get with the real clock = %v, want Optional[1]", got)
+	}
+}
+
+func TestExpiringConcurrent(t *testing.T) {
+	e, clock := newExpiring(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				switch j % 4 {
+				case 0:
+					e.SetFor("v", time.Millisecond)
+				case 1:
+					e.Get()
+				case 2:
+					e.ExpiresAt()
+				default:
+					if i == 0 {
+						clock.Advance(time.Millisecond)
+					} else {
+						e.Clear()
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
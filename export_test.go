@@ -0,0 +1,22 @@
+package optional
+
+import (
+	"hash/maphash"
+	"reflect"
+)
+
+// HashReflect is Hash as computed with the reflection-based fallback for
+// toolchains before Go 1.24.
+func HashReflect[T comparable](o Optional[T], seed maphash.Seed) uint64 {
+	return HashFunc(o, seed, func(h *maphash.Hash, v T) {
+		writeValue(h, reflect.ValueOf(&v).Elem())
+	})
+}
+
+// HashReflectAny is HashReflect for an interface value, which modules
+// declaring Go 1.20 or later can hash even though this one cannot.
+func HashReflectAny(o Optional[any], seed maphash.Seed) uint64 {
+	return HashFunc(o, seed, func(h *maphash.Hash, v any) {
+		writeValue(h, reflect.ValueOf(&v).Elem())
+	})
+}
@@ -0,0 +1,105 @@
+package optional
+
+import "context"
+
+// Future is a handle to an Optional being computed in the background by
+// Async. It is safe for concurrent use: every call to Await or TryGet made
+// after the computation finishes observes the same result.
+type Future[T any] struct {
+	done   chan struct{}
+	result Optional[T]
+}
+
+// Async calls fn with ctx in a new goroutine and returns a Future for its
+// result. A panic in fn is recovered and resolves the Future to an empty
+// Optional, so a failing prefetch cannot crash the process. Cancelling ctx is
+// up to fn to observe; the Future resolves when fn returns.
+//
+// Async takes ctx rather than fn alone because fn needs a context and Async
+// has no other one to give it: ctx carries the caller's deadline, cancellation
+// and values into fn, whereas the ctx passed to Await only bounds how long
+// that caller waits. To let the computation outlive the request, pass
+// context.Background() or a context detached from the request.
+//
+// Example:
+//
+//	avatar := optional.Async(ctx, func(ctx context.Context) optional.Optional[Avatar] {
+//	    return avatars.Lookup(ctx, userID)
+//	})
+//	page := renderMain(r)
+//	page.Avatar = avatar.Await(ctx)
+func Async[T any](ctx context.Context, fn func(context.Context) Optional[T]) *Future[T] {
+	f := &Future[T]{done: make(chan struct{})}
+	go func() {
+		defer close(f.done)
+		nested := OfRecover(func() Optional[T] { return fn(ctx) })
+		f.result = nested.OrElse(Empty[T]())
+	}()
+	return f
+}
+
+// Await blocks until the result is available or ctx is done. It returns an
+// empty Optional if ctx is done first; the computation keeps running and a
+// later call can still observe its result.
+func (f *Future[T]) Await(ctx context.Context) Optional[T] {
+	select {
+	case <-f.done:
+		return f.result
+	default:
+	}
+	select {
+	case <-f.done:
+		return f.result
+	case <-ctx.Done():
+		return Empty[T]()
+	}
+}
+
+// TryGet returns the result without blocking. The boolean is false if the
+// computation has not finished yet, in which case the Optional is empty.
+func (f *Future[T]) TryGet() (Optional[T], bool) {
+	select {
+	case <-f.done:
+		return f.result, true
+	default:
+		return Empty[T](), false
+	}
+}
+
+// Race calls every fn concurrently with a context derived from ctx and returns
+// the first present result. Once a result is chosen, the derived context is
+// cancelled so that the remaining suppliers can stop early. The result is
+// empty if every supplier returns empty or ctx is done first.
+//
+// A panic in a supplier is recovered and counts as an empty result. Race does
+// not wait for the losing suppliers to return, but none of them can block:
+// each goroutine exits as soon as its supplier returns.
+//
+// Example:
+//
+//	loc := optional.Race(ctx, maxmind.Lookup, ipinfo.Lookup, ipapi.Lookup)
+func Race[T any](ctx context.Context, fns ...func(context.Context) Optional[T]) Optional[T] {
+	if len(fns) == 0 {
+		return Empty[T]()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan Optional[T], len(fns))
+	for _, fn := range fns {
+		go func(fn func(context.Context) Optional[T]) {
+			nested := OfRecover(func() Optional[T] { return fn(ctx) })
+			results <- nested.OrElse(Empty[T]())
+		}(fn)
+	}
+	for range fns {
+		select {
+		case o := <-results:
+			if o.IsPresent() {
+				return o
+			}
+		case <-ctx.Done():
+			return Empty[T]()
+		}
+	}
+	return Empty[T]()
+}
@@ -0,0 +1,186 @@
+package optional
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAsyncAwait(t *testing.T) {
+	release := make(chan struct{})
+	f := Async(context.Background(), func(context.Context) Optional[int] {
+		<-release
+		return Of(42)
+	})
+	if got, ok := f.TryGet(); ok || got.IsPresent() {
+		t.Errorf("TryGet before resolution = %v, %v, want empty, false", got, ok)
+	}
+
+	const waiters = 50
+	results := make(chan Optional[int], waiters)
+	var wg sync.WaitGroup
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- f.Await(context.Background())
+		}()
+	}
+	close(release)
+	wg.Wait()
+	close(results)
+	for got := range results {
+		if got != Of(42) {
+			t.Errorf("Await = %v, want Optional[42]", got)
+		}
+	}
+	if got, ok := f.TryGet(); !ok || got != Of(42) {
+		t.Errorf("TryGet after resolution = %v, %v, want Optional[42], true", got, ok)
+	}
+}
+
+func TestAsyncPanic(t *testing.T) {
+	f := Async(context.Background(), func(context.Context) Optional[int] {
+		panic("boom")
+	})
+	if got := f.Await(context.Background()); got.IsPresent() {
+		t.Errorf("Await = %v, want empty", got)
+	}
+	if got, ok := f.TryGet(); !ok || got.IsPresent() {
+		t.Errorf("TryGet = %v, %v, want empty, true", got, ok)
+	}
+}
+
+func TestAwaitCancellation(t *testing.T) {
+	release := make(chan struct{})
+	f := Async(context.Background(), func(context.Context) Optional[string] {
+		<-release
+		return Of("late")
+	})
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got := f.Await(cancelled); got.IsPresent() {
+		t.Errorf("Await(cancelled) = %v, want empty", got)
+	}
+	timeout, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if got := f.Await(timeout); got.IsPresent() {
+		t.Errorf("Await(timeout) = %v, want empty", got)
+	}
+
+	// Giving up on Await does not cancel the computation.
+	close(release)
+	if got := f.Await(context.Background()); got != Of("late") {
+		t.Errorf("Await after release = %v, want Optional[late]", got)
+	}
+	// Once resolved, even a done ctx observes the result.
+	if got := f.Await(cancelled); got != Of("late") {
+		t.Errorf("Await(cancelled) after resolution = %v, want Optional[late]", got)
+	}
+}
+
+func TestAsyncContextReachesFn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := Async(ctx, func(ctx context.Context) Optional[error] {
+		<-ctx.Done()
+		return Of(ctx.Err())
+	})
+	cancel()
+	got, _ := f.Await(context.Background()).Get()
+	if !errors.Is(got, context.Canceled) {
+		t.Errorf("fn observed %v, want context.Canceled", got)
+	}
+}
+
+// supplier returns a Race competitor that answers with result after latency
+// unless its context is cancelled first. It reports the context error it
+// observed, nil if it answered, on errs.
+func supplier(latency time.Duration, result Optional[string], errs chan<- error) func(context.Context) Optional[string] {
+	return func(ctx context.Context) Optional[string] {
+		select {
+		case <-time.After(latency):
+			errs <- nil
+			return result
+		case <-ctx.Done():
+			errs <- ctx.Err()
+			return Empty[string]()
+		}
+	}
+}
+
+func TestRaceCancelsLosers(t *testing.T) {
+	errs := make(chan error, 3)
+	got := Race(context.Background(),
+		supplier(time.Second, Of("slow"), errs),
+		supplier(10*time.Millisecond, Of("fast"), errs),
+		supplier(2*time.Second, Of("slower"), errs),
+	)
+	if got != Of("fast") {
+		t.Fatalf("Race = %v, want Optional[fast]", got)
+	}
+	var answered, cancelled int
+	for i := 0; i < 3; i++ {
+		select {
+		case err := <-errs:
+			switch {
+			case err == nil:
+				answered++
+			case errors.Is(err, context.Canceled):
+				cancelled++
+			default:
+				t.Errorf("loser observed %v, want context.Canceled", err)
+			}
+		case <-time.After(500 * time.Millisecond):
+			t.Fatal("a losing supplier was not cancelled")
+		}
+	}
+	if answered != 1 || cancelled != 2 {
+		t.Errorf("%d answered and %d cancelled, want 1 and 2", answered, cancelled)
+	}
+}
+
+func TestRaceSkipsEmptyAndPanicking(t *testing.T) {
+	errs := make(chan error, 3)
+	got := Race(context.Background(),
+		func(context.Context) Optional[string] { panic("provider down") },
+		supplier(5*time.Millisecond, Empty[string](), errs),
+		supplier(20*time.Millisecond, Of("backup"), errs),
+	)
+	if got != Of("backup") {
+		t.Errorf("Race = %v, want Optional[backup]", got)
+	}
+}
+
+func TestRaceEmpty(t *testing.T) {
+	errs := make(chan error, 3)
+	tests := map[string]func() Optional[string]{
+		"no suppliers": func() Optional[string] {
+			return Race[string](context.Background())
+		},
+		"all empty": func() Optional[string] {
+			return Race(context.Background(),
+				supplier(time.Millisecond, Empty[string](), errs),
+				supplier(3*time.Millisecond, Empty[string](), errs),
+			)
+		},
+		"all panic": func() Optional[string] {
+			boom := func(context.Context) Optional[string] { panic("boom") }
+			return Race(context.Background(), boom, boom)
+		},
+		"parent cancelled": func() Optional[string] {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			return Race(ctx, supplier(time.Second, Of("late"), errs))
+		},
+	}
+	for name, race := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := race(); got.IsPresent() {
+				t.Errorf("Race = %v, want empty", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package optional
+
+import "hash/maphash"
+
+// Hash returns a hash of o that agrees with == on Optionals of a comparable
+// type: two Optionals that are both empty, or both present with values that
+// are ==, hash to the same value for the same seed. An empty Optional hashes
+// to a fixed value per seed, distinct from the hash of any present value
+// except by collision. This makes Optionals usable as keys of custom hash
+// tables.
+//
+// Note that Equals compares with reflect.DeepEqual, which can differ from ==,
+// for example for pointers to equal values. Like ==, Hash panics if T is an
+// interface type holding a value that is not comparable.
+//
+// Example:
+//
+//	seed := maphash.MakeSeed()
+//	optional.Hash(optional.Of("x"), seed) == optional.Hash(optional.Of("x"), seed) // true
+func Hash[T comparable](o Optional[T], seed maphash.Seed) uint64 {
+	return HashFunc(o, seed, writeComparable[T])
+}
+
+// HashFunc is like Hash, but hashes a present value with write, so that T need
+// not be comparable. write must write to h everything that distinguishes
+// values the caller considers unequal, and only that.
+//
+// Example:
+//
+//	h := optional.HashFunc(tags, seed, func(h *maphash.Hash, tags []string) {
+//	    for _, t := range tags {
+//	        h.WriteString(t)
+//	        h.WriteByte(0)
+//	    }
+//	})
+func HashFunc[T any](o Optional[T], seed maphash.Seed, write func(h *maphash.Hash, v T)) uint64 {
+	var h maphash.Hash
+	h.SetSeed(seed)
+	if !o.present {
+		h.WriteByte(0)
+		return h.Sum64()
+	}
+	h.WriteByte(1)
+	write(&h, o.value)
+	return h.Sum64()
+}
@@ -0,0 +1,9 @@
+//go:build go1.24
+
+package optional
+
+import "hash/maphash"
+
+func writeComparable[T comparable](h *maphash.Hash, v T) {
+	maphash.WriteComparable(h, v)
+}
@@ -0,0 +1,15 @@
+//go:build !go1.24
+
+package optional
+
+import (
+	"hash/maphash"
+	"reflect"
+)
+
+// writeComparable hashes v by walking it with reflection, for toolchains
+// without maphash.WriteComparable. Values that are == write the same bytes:
+// floats are hashed so that 0 and -0 agree, and pointers by address.
+func writeComparable[T comparable](h *maphash.Hash, v T) {
+	writeValue(h, reflect.ValueOf(&v).Elem())
+}
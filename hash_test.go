@@ -0,0 +1,191 @@
+package optional_test
+
+import (
+	"hash/maphash"
+	"math"
+	"testing"
+	"testing/quick"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+	"github.com/sergei-bronnikov/go-optional/optcmp"
+)
+
+// hashers are the implementations of Hash: the one selected for this
+// toolchain and the reflection-based fallback, which is tested everywhere.
+func hashers[T comparable]() map[string]func(optional.Optional[T], maphash.Seed) uint64 {
+	return map[string]func(optional.Optional[T], maphash.Seed) uint64{
+		"Hash":        optional.Hash[T],
+		"HashReflect": optional.HashReflect[T],
+	}
+}
+
+// agrees reports whether both hashers give a and b the same hash whenever
+// they are equal under optcmp, that is both empty or both present with ==
+// values.
+func agrees[T comparable](t *testing.T, seed maphash.Seed, a, b optcmp.Optional[T]) bool {
+	t.Helper()
+	if a != b {
+		return true
+	}
+	for name, hash := range hashers[T]() {
+		if hash(a.ToOptional(), seed) != hash(b.ToOptional(), seed) {
+			t.Errorf("%s(%v) != %s(%v), but they are ==", name, a, name, b)
+			return false
+		}
+	}
+	return true
+}
+
+// pick returns vals[i] modulo their number, or an empty Optional, so that
+// quick generates equal pairs often.
+func pick[T comparable](vals []T, i uint8, present bool) optcmp.Optional[T] {
+	if !present {
+		return optcmp.Empty[T]()
+	}
+	return optcmp.Of(vals[int(i)%len(vals)])
+}
+
+type record struct {
+	ID    int
+	Name  string
+	Score float64
+	Flags [2]bool
+	Ref   *int
+}
+
+func TestHashAgreesWithEquality(t *testing.T) {
+	seed := maphash.MakeSeed()
+	x, y := 1, 1
+	negZero := math.Copysign(0, -1)
+
+	ints := []int{0, 1, -1, math.MaxInt}
+	strs := []string{"", "a", "ab", "a\x00", "b"}
+	floats := []float64{0, negZero, 1, math.Inf(1), math.Inf(-1), math.NaN()}
+	complexes := []complex128{0, complex(negZero, 0), complex(0, negZero), complex(1, 1)}
+	ptrs := []*int{nil, &x, &y}
+	records := []record{
+		{},
+		{ID: 1, Name: "a"},
+		{ID: 1, Name: "a", Score: negZero},
+		{ID: 1, Name: "a", Flags: [2]bool{true}},
+		{ID: 1, Name: "a", Ref: &x},
+		{ID: 1, Name: "a", Ref: &y},
+	}
+
+	tests := map[string]any{
+		"int": func(i, j uint8, pi, pj bool) bool {
+			return agrees(t, seed, pick(ints, i, pi), pick(ints, j, pj))
+		},
+		"string": func(i, j uint8, pi, pj bool) bool {
+			return agrees(t, seed, pick(strs, i, pi), pick(strs, j, pj))
+		},
+		"float64": func(i, j uint8, pi, pj bool) bool {
+			return agrees(t, seed, pick(floats, i, pi), pick(floats, j, pj))
+		},
+		"float32": func(i, j uint8, pi, pj bool) bool {
+			a, b := pick(floats, i, pi), pick(floats, j, pj)
+			return agrees(t, seed, toFloat32(a), toFloat32(b))
+		},
+		"complex128": func(i, j uint8, pi, pj bool) bool {
+			return agrees(t, seed, pick(complexes, i, pi), pick(complexes, j, pj))
+		},
+		"pointer": func(i, j uint8, pi, pj bool) bool {
+			return agrees(t, seed, pick(ptrs, i, pi), pick(ptrs, j, pj))
+		},
+		"struct": func(i, j uint8, pi, pj bool) bool {
+			return agrees(t, seed, pick(records, i, pi), pick(records, j, pj))
+		},
+		"arbitrary int64": func(a, b int64, pa, pb bool) bool {
+			return agrees(t, seed, pick([]int64{a}, 0, pa), pick([]int64{b}, 0, pb)) &&
+				agrees(t, seed, pick([]int64{a}, 0, pa), pick([]int64{a}, 0, pb))
+		},
+		"arbitrary string": func(a, b string, pa bool) bool {
+			return agrees(t, seed, pick([]string{a}, 0, pa), pick([]string{b}, 0, pa)) &&
+				agrees(t, seed, pick([]string{a}, 0, pa), pick([]string{string([]byte(a))}, 0, pa))
+		},
+	}
+	for name, f := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := quick.Check(f, &quick.Config{MaxCount: 2000}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func toFloat32(o optcmp.Optional[float64]) optcmp.Optional[float32] {
+	if v, ok := o.Get(); ok {
+		return optcmp.Of(float32(v))
+	}
+	return optcmp.Empty[float32]()
+}
+
+// Interface values are hashed only by the fallback here, since this module's
+// Go version does not let any satisfy comparable.
+func TestHashReflectInterfaceAgreesWithEquality(t *testing.T) {
+	seed := maphash.MakeSeed()
+	x, y := 1, 1
+	anys := []any{nil, 1, int64(1), "1", 1.0, 0.0, math.Copysign(0, -1), [1]int{1}, &x, &y, record{ID: 1}}
+	f := func(i, j uint8, pi, pj bool) bool {
+		a, b := optional.Empty[any](), optional.Empty[any]()
+		if pi {
+			a = optional.Of(anys[int(i)%len(anys)])
+		}
+		if pj {
+			b = optional.Of(anys[int(j)%len(anys)])
+		}
+		if a == b && optional.HashReflectAny(a, seed) != optional.HashReflectAny(b, seed) {
+			t.Errorf("HashReflectAny(%v) != HashReflectAny(%v), but they are ==", a, b)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+}
+
+// Hashes of different values must differ, or Hash would be useless for hash
+// tables; with a random seed a collision among these is vanishingly unlikely.
+func TestHashDistinguishes(t *testing.T) {
+	seed := maphash.MakeSeed()
+	x := 1
+	values := []optional.Optional[record]{
+		optional.Empty[record](), optional.Of(record{}), optional.Of(record{ID: 1}),
+		optional.Of(record{Name: "ab"}), optional.Of(record{Name: "a", Flags: [2]bool{true}}),
+		optional.Of(record{Flags: [2]bool{false, true}}), optional.Of(record{Score: 1}),
+		optional.Of(record{Ref: &x}),
+	}
+	for name, hash := range hashers[record]() {
+		seen := map[uint64]int{}
+		for i, v := range values {
+			h := hash(v, seed)
+			if j, ok := seen[h]; ok {
+				t.Errorf("%s: %v and %v collide", name, values[j], v)
+			}
+			seen[h] = i
+		}
+	}
+
+	anys := []optional.Optional[any]{
+		optional.Empty[any](), optional.Of[any](nil), optional.Of[any](0), optional.Of[any](int64(0)),
+		optional.Of[any](""), optional.Of[any]("0"), optional.Of[any](false), optional.Of[any](0.0),
+	}
+	seen := map[uint64]int{}
+	for i, v := range anys {
+		h := optional.HashReflectAny(v, seed)
+		if j, ok := seen[h]; ok {
+			t.Errorf("HashReflectAny: %v and %v collide", anys[j], v)
+		}
+		seen[h] = i
+	}
+}
+
+func TestHashReflectPanicsOnNonComparable(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("HashReflectAny of an interface holding a slice did not panic")
+		}
+	}()
+	optional.HashReflectAny(optional.Of[any]([]int{1}), maphash.MakeSeed())
+}
@@ -0,0 +1,126 @@
+package parse
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type level string
+
+func (l *level) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "debug", "info":
+		*l = level("L:" + string(b))
+		return nil
+	}
+	return errors.New("unknown level")
+}
+
+type port uint16
+
+func TestValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		typ    reflect.Type
+		layout string
+		want   any
+	}{
+		{"string", "hello", reflect.TypeOf(""), "", "hello"},
+		{"empty string", "", reflect.TypeOf(""), "", ""},
+		{"bool", "true", reflect.TypeOf(false), "", true},
+		{"bool short", "0", reflect.TypeOf(false), "", false},
+		{"int", "-42", reflect.TypeOf(0), "", -42},
+		{"int8", "127", reflect.TypeOf(int8(0)), "", int8(127)},
+		{"int64", "9223372036854775807", reflect.TypeOf(int64(0)), "", int64(9223372036854775807)},
+		{"uint8", "255", reflect.TypeOf(uint8(0)), "", uint8(255)},
+		{"named uint", "8080", reflect.TypeOf(port(0)), "", port(8080)},
+		{"float32", "1.5", reflect.TypeOf(float32(0)), "", float32(1.5)},
+		{"float64", "1e3", reflect.TypeOf(0.0), "", 1000.0},
+		{"duration", "1m30s", reflect.TypeOf(time.Duration(0)), "", 90 * time.Second},
+		{"time default layout", "2024-01-02T15:04:05Z", reflect.TypeOf(time.Time{}), "", time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"time layout", "02.01.2024", reflect.TypeOf(time.Time{}), "02.01.2006", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"text", "10.0.0.1", reflect.TypeOf(netip.Addr{}), "", netip.MustParseAddr("10.0.0.1")},
+		{"text over kind", "debug", reflect.TypeOf(level("")), "", level("L:debug")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Value(tt.in, tt.typ, tt.layout)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Type() != tt.typ {
+				t.Errorf("type = %s, want %s", got.Type(), tt.typ)
+			}
+			if !reflect.DeepEqual(got.Interface(), tt.want) {
+				t.Errorf("Value(%q) = %#v, want %#v", tt.in, got.Interface(), tt.want)
+			}
+		})
+	}
+}
+
+func TestValueError(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		typ     reflect.Type
+		layout  string
+		is      error
+		wantMsg string
+	}{
+		{"bool", "yes", reflect.TypeOf(false), "", strconv.ErrSyntax, ""},
+		{"int syntax", "4x", reflect.TypeOf(0), "", strconv.ErrSyntax, ""},
+		{"int8 range", "128", reflect.TypeOf(int8(0)), "", strconv.ErrRange, ""},
+		{"uint negative", "-1", reflect.TypeOf(uint(0)), "", strconv.ErrSyntax, ""},
+		{"uint16 range", "65536", reflect.TypeOf(port(0)), "", strconv.ErrRange, ""},
+		{"float", "one", reflect.TypeOf(0.0), "", strconv.ErrSyntax, ""},
+		{"duration", "90", reflect.TypeOf(time.Duration(0)), "", nil, ""},
+		{"time layout mismatch", "2024-01-02", reflect.TypeOf(time.Time{}), "02.01.2006", nil, ""},
+		{"text", "trace", reflect.TypeOf(level("")), "", nil, "unknown level"},
+		{"unsupported slice", "a,b", reflect.TypeOf([]string(nil)), "", nil, "unsupported type []string"},
+		{"unsupported struct", "x", reflect.TypeOf(struct{}{}), "", nil, "unsupported type struct {}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Value(tt.in, tt.typ, tt.layout)
+			if err == nil {
+				t.Fatalf("Value(%q) = %v, want an error", tt.in, got)
+			}
+			if got.IsValid() {
+				t.Errorf("Value returned %v alongside the error", got)
+			}
+			if tt.is != nil && !errors.Is(err, tt.is) {
+				t.Errorf("error = %v, want one wrapping %v", err, tt.is)
+			}
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Errorf("error = %q, want %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestSupported(t *testing.T) {
+	for _, tt := range []struct {
+		v    any
+		want bool
+	}{
+		{"", true}, {false, true}, {0, true}, {int8(0), true}, {uint64(0), true},
+		{float32(0), true}, {port(0), true}, {time.Duration(0), true}, {time.Time{}, true},
+		{netip.Addr{}, true}, {level(""), true},
+		{[]string(nil), false}, {map[string]int(nil), false}, {struct{}{}, false},
+		{new(int), false}, {complex64(0), false},
+	} {
+		typ := reflect.TypeOf(tt.v)
+		if got := Supported(typ); got != tt.want {
+			t.Errorf("Supported(%s) = %v, want %v", typ, got, tt.want)
+		}
+		// Supported and Value must agree on which types they handle.
+		if _, err := Value("", typ, ""); !tt.want && (err == nil || err.Error() != "unsupported type "+typ.String()) {
+			t.Errorf("Value on unsupported %s error = %v", typ, err)
+		}
+	}
+}
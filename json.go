@@ -0,0 +1,31 @@
+package optional
+
+import "encoding/json"
+
+// MarshalMapOmitEmpty encodes m as a JSON object containing only its present
+// entries; empty entries are left out entirely rather than encoded as null.
+// Keys and values are encoded by encoding/json under its usual rules, so keys
+// must be strings, integers or encoding.TextMarshalers, and keys appear in
+// sorted order.
+//
+// A present nil value, such as Of[any](nil), is encoded as null. This gives
+// per-entry control over absent versus explicit null for APIs that tell them
+// apart.
+//
+// Example:
+//
+//	body, err := optional.MarshalMapOmitEmpty(map[string]optional.Optional[any]{
+//	    "name":  optional.Of[any]("Ada"),
+//	    "email": optional.Empty[any](),  // omitted
+//	    "phone": optional.Of[any](nil), // null
+//	})
+//	// body: {"name":"Ada","phone":null}
+func MarshalMapOmitEmpty[K comparable, V any](m map[K]Optional[V]) ([]byte, error) {
+	present := make(map[K]V, len(m))
+	for k, o := range m {
+		if o.present {
+			present[k] = o.value
+		}
+	}
+	return json.Marshal(present)
+}
@@ -0,0 +1,82 @@
+package optional
+
+import (
+	"bytes"
+	"flag"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+type level int
+
+func (l level) MarshalText() ([]byte, error) {
+	return []byte([]string{"debug", "info", "warn"}[l]), nil
+}
+
+func TestMarshalMapOmitEmptyGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		marshal func() ([]byte, error)
+	}{
+		{"nil", func() ([]byte, error) { return MarshalMapOmitEmpty[string, int](nil) }},
+		{"all_empty", func() ([]byte, error) {
+			return MarshalMapOmitEmpty(map[string]Optional[int]{"a": Empty[int](), "b": Empty[int]()})
+		}},
+		{"string_keys", func() ([]byte, error) {
+			return MarshalMapOmitEmpty(map[string]Optional[any]{
+				"name":  Of[any]("Ada <admin>"),
+				"email": Empty[any](),
+				"phone": Of[any](nil),
+				"age":   Of[any](36),
+				"tags":  Of[any]([]string{"a", "b"}),
+			})
+		}},
+		{"int_keys", func() ([]byte, error) {
+			return MarshalMapOmitEmpty(map[int]Optional[string]{10: Of("ten"), 2: Of("two"), -1: Of(""), 3: Empty[string]()})
+		}},
+		{"uint_keys", func() ([]byte, error) {
+			return MarshalMapOmitEmpty(map[uint8]Optional[bool]{0: Of(false), 255: Of(true), 7: Empty[bool]()})
+		}},
+		{"text_keys", func() ([]byte, error) {
+			return MarshalMapOmitEmpty(map[level]Optional[int]{0: Of(1), 1: Empty[int](), 2: Of(3)})
+		}},
+		{"addr_keys", func() ([]byte, error) {
+			return MarshalMapOmitEmpty(map[netip.Addr]Optional[string]{
+				netip.MustParseAddr("10.0.0.2"): Of("b"),
+				netip.MustParseAddr("10.0.0.1"): Of("a"),
+				netip.MustParseAddr("::1"):      Empty[string](),
+			})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.marshal()
+			if err != nil {
+				t.Fatalf("MarshalMapOmitEmpty = %v", err)
+			}
+			golden := filepath.Join("testdata", "omitempty", tt.name+".json")
+			if *update {
+				if err := os.WriteFile(golden, append(got, '\n'), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, bytes.TrimSuffix(want, []byte("\n"))) {
+				t.Errorf("MarshalMapOmitEmpty =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestMarshalMapOmitEmptyUnsupportedKey(t *testing.T) {
+	if _, err := MarshalMapOmitEmpty(map[[2]int]Optional[int]{{1, 2}: Of(1)}); err == nil {
+		t.Error("MarshalMapOmitEmpty with array keys succeeded")
+	}
+}
@@ -0,0 +1,129 @@
+package optional
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FromJSONPath extracts the value at path from the JSON document data and
+// decodes it into a T with encoding/json. The result is empty if any segment
+// of the path is missing, indexes past the end of an array, or walks into a
+// value of the wrong kind, and if the value found is null.
+//
+// A path is a sequence of object keys separated by dots, with array indices
+// in brackets: "user.address[0].zip". A backslash escapes the next character,
+// so "headers.X-Trace\.Id" names the key "X-Trace.Id". The empty path refers
+// to the whole document.
+//
+// An error is returned only if data is not valid JSON, path is malformed, or
+// the value found cannot be decoded into T; for example 1.5 cannot be decoded
+// into an int64, while into an any it becomes a float64.
+//
+// Example:
+//
+//	zip, err := optional.FromJSONPath[string](payload, "user.address[0].zip")
+//	retries, err := optional.FromJSONPath[int64](payload, "meta.retries")
+func FromJSONPath[T any](data []byte, path string) (Optional[T], error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return Empty[T](), err
+	}
+	if !json.Valid(data) {
+		return Empty[T](), fmt.Errorf("optional: FromJSONPath %q: invalid JSON", path)
+	}
+	raw := bytes.TrimSpace(data)
+	for _, seg := range segments {
+		var ok bool
+		if raw, ok = seg.lookup(raw); !ok {
+			return Empty[T](), nil
+		}
+	}
+	if bytes.Equal(raw, []byte("null")) {
+		return Empty[T](), nil
+	}
+	var val T
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return Empty[T](), fmt.Errorf("optional: FromJSONPath %q: %w", path, err)
+	}
+	return Of(val), nil
+}
+
+// jsonPathSegment is an object key, or an array index if isIndex is set.
+type jsonPathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// lookup returns the member of the valid JSON value raw named by s, and
+// whether it exists.
+func (s jsonPathSegment) lookup(raw []byte) ([]byte, bool) {
+	if s.isIndex {
+		if len(raw) == 0 || raw[0] != '[' {
+			return nil, false
+		}
+		var elems []json.RawMessage
+		if json.Unmarshal(raw, &elems) != nil || s.index >= len(elems) {
+			return nil, false
+		}
+		return bytes.TrimSpace(elems[s.index]), true
+	}
+	if len(raw) == 0 || raw[0] != '{' {
+		return nil, false
+	}
+	var members map[string]json.RawMessage
+	if json.Unmarshal(raw, &members) != nil {
+		return nil, false
+	}
+	member, ok := members[s.key]
+	return bytes.TrimSpace(member), ok
+}
+
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	var segments []jsonPathSegment
+	malformed := func(reason string) error {
+		return errors.New("optional: FromJSONPath: malformed path " + strconv.Quote(path) + ": " + reason)
+	}
+	for i := 0; i < len(path); {
+		switch {
+		case path[i] == '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, malformed("unterminated [")
+			}
+			digits := path[i+1 : i+end]
+			n, err := strconv.Atoi(digits)
+			if err != nil || digits[0] < '0' || digits[0] > '9' {
+				return nil, malformed("invalid index " + strconv.Quote(digits))
+			}
+			segments = append(segments, jsonPathSegment{index: n, isIndex: true})
+			i += end + 1
+			if i < len(path) && path[i] != '.' && path[i] != '[' {
+				return nil, malformed("expected . or [ after ]")
+			}
+		case path[i] == '.' && len(segments) > 0:
+			i++
+			fallthrough
+		default:
+			var key strings.Builder
+			for ; i < len(path) && path[i] != '.' && path[i] != '['; i++ {
+				if path[i] == '\\' {
+					i++
+					if i == len(path) {
+						return nil, malformed("trailing backslash")
+					}
+				}
+				key.WriteByte(path[i])
+			}
+			if key.Len() == 0 {
+				return nil, malformed("empty key")
+			}
+			segments = append(segments, jsonPathSegment{key: key.String()})
+		}
+	}
+	return segments, nil
+}
@@ -0,0 +1,106 @@
+package optional
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const webhook = `{
+	"user": {
+		"name": "ada",
+		"nick": null,
+		"address": [{"zip": "10115"}, {"zip": null}],
+		"age": 36,
+		"score": 1.5
+	},
+	"headers": {"X-Trace.Id": "t-1", "a\\b": "backslash"},
+	"ids": [[1, 2], [3]],
+	"big": 9007199254740993
+}`
+
+type bigOnly struct {
+	Big int64 `json:"big"`
+}
+
+// pathCase decodes path into a T and compares the result.
+type pathCase[T any] struct {
+	path    string
+	want    Optional[T]
+	wantErr string
+}
+
+func (c pathCase[T]) run(t *testing.T) {
+	got, err := FromJSONPath[T]([]byte(webhook), c.path)
+	if c.wantErr != "" {
+		if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+			t.Fatalf("FromJSONPath(%q) error = %v, want one containing %q", c.path, err, c.wantErr)
+		}
+		if got.IsPresent() {
+			t.Errorf("FromJSONPath(%q) = %v with an error, want empty", c.path, got)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("FromJSONPath(%q) error = %v", c.path, err)
+	}
+	if !reflect.DeepEqual(got, c.want) {
+		t.Errorf("FromJSONPath(%q) = %v, want %v", c.path, got, c.want)
+	}
+}
+
+func TestFromJSONPath(t *testing.T) {
+	tests := map[string]interface{ run(*testing.T) }{
+		"key":                  pathCase[string]{path: "user.name", want: Of("ada")},
+		"array index":          pathCase[string]{path: "user.address[0].zip", want: Of("10115")},
+		"nested indices":       pathCase[int]{path: "ids[0][1]", want: Of(2)},
+		"leading index":        pathCase[[]int]{path: "ids[1]", want: Of([]int{3})},
+		"index past the end":   pathCase[string]{path: "user.address[2].zip", want: Empty[string]()},
+		"index into an object": pathCase[string]{path: "user[0]", want: Empty[string]()},
+		"key of an array":      pathCase[string]{path: "ids.zip", want: Empty[string]()},
+		"key of a scalar":      pathCase[string]{path: "user.name.first", want: Empty[string]()},
+		"escaped dot":          pathCase[string]{path: `headers.X-Trace\.Id`, want: Of("t-1")},
+		"escaped backslash":    pathCase[string]{path: `headers.a\\b`, want: Of("backslash")},
+		"unescaped dot":        pathCase[string]{path: "headers.X-Trace.Id", want: Empty[string]()},
+		"missing key":          pathCase[string]{path: "user.email", want: Empty[string]()},
+		"missing parent":       pathCase[string]{path: "account.email", want: Empty[string]()},
+		"explicit null":        pathCase[string]{path: "user.nick", want: Empty[string]()},
+		"null in an array":     pathCase[string]{path: "user.address[1].zip", want: Empty[string]()},
+		"null as pointer":      pathCase[*string]{path: "user.nick", want: Empty[*string]()},
+		"int64 leaf":           pathCase[int64]{path: "user.age", want: Of(int64(36))},
+		"big int64 leaf":       pathCase[int64]{path: "big", want: Of(int64(9007199254740993))},
+		"float64 leaf":         pathCase[float64]{path: "user.score", want: Of(1.5)},
+		"int leaf as float64":  pathCase[float64]{path: "user.age", want: Of(36.0)},
+		"number as any":        pathCase[any]{path: "user.age", want: Of[any](36.0)},
+		"big float64 leaf":     pathCase[float64]{path: "big", want: Of(9007199254740992.0)},
+		"whole document":       pathCase[bigOnly]{path: "", want: Of(bigOnly{Big: 9007199254740993})},
+		"float into int64":     pathCase[int64]{path: "user.score", wantErr: "cannot unmarshal number 1.5"},
+		"string into int64":    pathCase[int64]{path: "user.name", wantErr: "cannot unmarshal string"},
+		"object into string":   pathCase[string]{path: "user", wantErr: "cannot unmarshal object"},
+		"unterminated bracket": pathCase[string]{path: "ids[0", wantErr: "unterminated ["},
+		"empty index":          pathCase[string]{path: "ids[]", wantErr: `invalid index ""`},
+		"negative index":       pathCase[string]{path: "ids[-1]", wantErr: `invalid index "-1"`},
+		"signed index":         pathCase[string]{path: "ids[+1]", wantErr: `invalid index "+1"`},
+		"text after bracket":   pathCase[string]{path: "ids[0]x", wantErr: "expected . or [ after ]"},
+		"empty key":            pathCase[string]{path: "user..name", wantErr: "empty key"},
+		"trailing dot":         pathCase[string]{path: "user.", wantErr: "empty key"},
+		"leading dot":          pathCase[string]{path: ".user", wantErr: "empty key"},
+		"trailing backslash":   pathCase[string]{path: `user\`, wantErr: "trailing backslash"},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.run)
+	}
+}
+
+func TestFromJSONPathInvalidJSON(t *testing.T) {
+	for _, data := range []string{``, `{"user":`, `{"a":1} {"b":2}`, `nul`} {
+		got, err := FromJSONPath[string]([]byte(data), "user")
+		if err == nil || !strings.Contains(err.Error(), "invalid JSON") || got.IsPresent() {
+			t.Errorf("FromJSONPath(%q) = %v, %v, want an invalid JSON error", data, got, err)
+		}
+	}
+	// A malformed path is reported even before the document is inspected.
+	if _, err := FromJSONPath[string]([]byte(`x`), "a["); err == nil || !strings.Contains(err.Error(), "malformed path") {
+		t.Errorf("error = %v, want a malformed path error", err)
+	}
+}
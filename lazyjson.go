@@ -0,0 +1,84 @@
+package optional
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// LazyJSON is an Optional JSON field that is decoded on first access rather
+// than when the enclosing document is unmarshaled. It suits large, rarely
+// used sub-objects: UnmarshalJSON only copies the raw bytes, and the cost of
+// decoding them into T is paid by the first call to Get.
+//
+// A missing key or a JSON null leaves a LazyJSON empty. The zero value is
+// empty. Get caches its result in the LazyJSON, so, like other decoded
+// values, a LazyJSON must not be read from multiple goroutines without
+// synchronization.
+type LazyJSON[T any] struct {
+	raw     Optional[json.RawMessage]
+	value   Optional[T]
+	err     error
+	decoded bool
+}
+
+// Get decodes the raw message into a T on the first call and returns the
+// result, empty if there is no message. Later calls return the same result
+// without decoding again. A decoding error is returned, not mapped to empty.
+//
+// Example:
+//
+//	type Envelope struct {
+//	    ID      string                       `json:"id"`
+//	    Details optional.LazyJSON[BigReport] `json:"details"`
+//	}
+//	report, err := env.Details.Get() // decoded only here
+func (l *LazyJSON[T]) Get() (Optional[T], error) {
+	if l.decoded {
+		return l.value, l.err
+	}
+	l.decoded = true
+	raw, ok := l.raw.Get()
+	if !ok {
+		return l.value, nil
+	}
+	var val T
+	if err := json.Unmarshal(raw, &val); err != nil {
+		l.err = err
+		return l.value, err
+	}
+	l.value = Of(val)
+	return l.value, nil
+}
+
+// Raw returns the raw message, empty if the key was missing or null, for
+// passing the value through without decoding it.
+func (l LazyJSON[T]) Raw() Optional[json.RawMessage] {
+	return l.raw
+}
+
+// UnmarshalJSON implements json.Unmarshaler by storing a copy of data, or
+// making l empty if data is null. Any value decoded earlier is discarded.
+func (l *LazyJSON[T]) UnmarshalJSON(data []byte) error {
+	*l = LazyJSON[T]{}
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	l.raw = Of(json.RawMessage(append([]byte(nil), data...)))
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. Until Get has decoded the value, the
+// original raw bytes are emitted unchanged, so a round trip keeps key order,
+// number formatting and unknown fields; encoding/json still compacts
+// whitespace, as it does for every Marshaler. Once decoded, the value is
+// encoded again, so changes made through reference types such as maps are
+// included. An empty LazyJSON encodes as null.
+func (l LazyJSON[T]) MarshalJSON() ([]byte, error) {
+	if val, ok := l.value.Get(); ok {
+		return json.Marshal(val)
+	}
+	if raw, ok := l.raw.Get(); ok {
+		return raw, nil
+	}
+	return []byte("null"), nil
+}
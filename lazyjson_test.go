@@ -0,0 +1,120 @@
+package optional
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type report struct {
+	Rows []reportRow `json:"rows"`
+}
+
+type reportRow struct {
+	ID    int               `json:"id"`
+	Name  string            `json:"name"`
+	Score float64           `json:"score"`
+	Tags  []string          `json:"tags"`
+	Attrs map[string]string `json:"attrs"`
+}
+
+// envelopeJSON returns a document with a small header and a details object
+// of n rows.
+func envelopeJSON(n int) []byte {
+	var b strings.Builder
+	b.WriteString(`{"id":"env-1","details":{"rows":[`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(`{"id":` + strconv.Itoa(i) + `,"name":"row","score":1.5,"tags":["a","b"],"attrs":{"k":"v"}}`)
+	}
+	b.WriteString(`]}}`)
+	return []byte(b.String())
+}
+
+func TestLazyJSON(t *testing.T) {
+	var env struct {
+		ID      string           `json:"id"`
+		Details LazyJSON[report] `json:"details"`
+		Missing LazyJSON[report] `json:"missing"`
+		Null    LazyJSON[report] `json:"null"`
+		Bad     LazyJSON[int]    `json:"bad"`
+	}
+	data := []byte(`{"id":"x","details":{"rows":[{"id":7}],"extra":1},"null":null,"bad":"s"}`)
+	if err := json.Unmarshal(data, &env); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := json.Marshal(env.Details); string(got) != `{"rows":[{"id":7}],"extra":1}` {
+		t.Errorf("Marshal before Get = %s, want the raw bytes", got)
+	}
+	rep, err := env.Details.Get()
+	if v, ok := rep.Get(); err != nil || !ok || len(v.Rows) != 1 || v.Rows[0].ID != 7 {
+		t.Errorf("Get = %v, %v", rep, err)
+	}
+	if got, _ := json.Marshal(env.Details); !strings.HasPrefix(string(got), `{"rows":[{"id":7,`) {
+		t.Errorf("Marshal after Get = %s, want the re-encoded value", got)
+	}
+	for name, l := range map[string]*LazyJSON[report]{"missing": &env.Missing, "null": &env.Null} {
+		if got, err := l.Get(); got.IsPresent() || err != nil {
+			t.Errorf("%s: Get = %v, %v, want empty, nil", name, got, err)
+		}
+	}
+	if _, err := env.Bad.Get(); err == nil {
+		t.Error("Get of a string into LazyJSON[int] succeeded")
+	}
+	if _, err := env.Bad.Get(); err == nil {
+		t.Error("second Get lost the decoding error")
+	}
+}
+
+// BenchmarkUnmarshalDetails compares decoding a document whose details are
+// never read, eagerly and with LazyJSON, and the lazy cost when they are.
+func BenchmarkUnmarshalDetails(b *testing.B) {
+	for _, rows := range []int{10, 1000} {
+		data := envelopeJSON(rows)
+		b.Run(strconv.Itoa(rows)+"/eager", func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var env struct {
+					ID      string `json:"id"`
+					Details report `json:"details"`
+				}
+				if err := json.Unmarshal(data, &env); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(strconv.Itoa(rows)+"/lazy", func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var env struct {
+					ID      string           `json:"id"`
+					Details LazyJSON[report] `json:"details"`
+				}
+				if err := json.Unmarshal(data, &env); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(strconv.Itoa(rows)+"/lazy+Get", func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var env struct {
+					ID      string           `json:"id"`
+					Details LazyJSON[report] `json:"details"`
+				}
+				if err := json.Unmarshal(data, &env); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := env.Details.Get(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package optional
+
+import (
+	"container/list"
+	"sync"
+)
+
+// MemoizeOption configures Memoize.
+type MemoizeOption func(*memoizeConfig)
+
+type memoizeConfig struct {
+	noCacheEmpty bool
+	maxSize      int
+}
+
+// NoCacheEmpty makes Memoize cache only present results, so a key whose lookup
+// returned empty is looked up again on its next call. Concurrent callers
+// waiting on the same in-flight call still share its empty result.
+func NoCacheEmpty() MemoizeOption {
+	return func(c *memoizeConfig) {
+		c.noCacheEmpty = true
+	}
+}
+
+// WithMaxSize bounds the cache to n keys, evicting the least recently used key
+// when a new one is added. A size of zero or less means unbounded, which is the
+// default.
+func WithMaxSize(n int) MemoizeOption {
+	return func(c *memoizeConfig) {
+		c.maxSize = n
+	}
+}
+
+// memoEntry is the cached or in-flight result for one key.
+type memoEntry[K comparable, V any] struct {
+	key    K
+	done   chan struct{}
+	result Optional[V]
+	ok     bool // fn returned normally
+	elem   *list.Element
+}
+
+// Memoize returns a function that caches the results of fn by key. Both
+// present and empty results are cached, unless NoCacheEmpty is given. The
+// returned function is safe for concurrent use, and fn is called at most once
+// per cached key: concurrent first calls for the same key wait for a single
+// call to fn and share its result.
+//
+// If fn panics, the panic propagates to the caller that made the call, nothing
+// is cached, and callers waiting on that call retry it themselves.
+//
+// Example:
+//
+//	lookup := optional.Memoize(profiles.Find, optional.WithMaxSize(1000))
+//	profile := lookup(userID) // func(string) Optional[Profile]
+func Memoize[K comparable, V any](fn func(K) Optional[V], opts ...MemoizeOption) func(K) Optional[V] {
+	var cfg memoizeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	var (
+		mu      sync.Mutex
+		entries = make(map[K]*memoEntry[K, V])
+		lru     = list.New() // front is most recently used
+	)
+	remove := func(e *memoEntry[K, V]) {
+		if entries[e.key] == e {
+			delete(entries, e.key)
+			lru.Remove(e.elem)
+		}
+	}
+	call := func(key K, e *memoEntry[K, V]) {
+		defer func() {
+			mu.Lock()
+			if !e.ok || (cfg.noCacheEmpty && e.result.IsEmpty()) {
+				remove(e)
+			}
+			mu.Unlock()
+			close(e.done)
+		}()
+		e.result = fn(key)
+		e.ok = true
+	}
+	return func(key K) Optional[V] {
+		for {
+			mu.Lock()
+			e, found := entries[key]
+			if found {
+				lru.MoveToFront(e.elem)
+				mu.Unlock()
+				<-e.done
+				if e.ok {
+					return e.result
+				}
+				continue
+			}
+			e = &memoEntry[K, V]{key: key, done: make(chan struct{})}
+			e.elem = lru.PushFront(e)
+			entries[key] = e
+			if cfg.maxSize > 0 && lru.Len() > cfg.maxSize {
+				remove(lru.Back().Value.(*memoEntry[K, V]))
+			}
+			mu.Unlock()
+			call(key, e)
+			return e.result
+		}
+	}
+}
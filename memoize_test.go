@@ -0,0 +1,156 @@
+package optional
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// counter wraps a lookup function and counts its calls per key.
+type counter struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (c *counter) wrap(fn func(string) Optional[int]) func(string) Optional[int] {
+	c.calls = make(map[string]int)
+	return func(key string) Optional[int] {
+		c.mu.Lock()
+		c.calls[key]++
+		c.mu.Unlock()
+		return fn(key)
+	}
+}
+
+func (c *counter) count(key string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[key]
+}
+
+func parse(key string) Optional[int] {
+	n, err := strconv.Atoi(key)
+	if err != nil {
+		return Empty[int]()
+	}
+	return Of(n)
+}
+
+func TestMemoizeConcurrentCalls(t *testing.T) {
+	var c counter
+	release := make(chan struct{})
+	lookup := Memoize(c.wrap(func(key string) Optional[int] {
+		<-release
+		return parse(key)
+	}))
+
+	keys := []string{"1", "2", "3", "x"}
+	const callers = 50
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		for _, key := range keys {
+			wg.Add(1)
+			go func(key string) {
+				defer wg.Done()
+				if got, want := lookup(key), parse(key); got != want {
+					t.Errorf("lookup(%q) = %v, want %v", key, got, want)
+				}
+			}(key)
+		}
+	}
+	close(release)
+	wg.Wait()
+	for _, key := range keys {
+		if n := c.count(key); n != 1 {
+			t.Errorf("fn called %d times for %q, want 1", n, key)
+		}
+	}
+}
+
+func TestMemoizeEmptyResults(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []MemoizeOption
+		calls int
+	}{
+		{"cached by default", nil, 1},
+		{"NoCacheEmpty", []MemoizeOption{NoCacheEmpty()}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c counter
+			lookup := Memoize(c.wrap(parse), tt.opts...)
+			for i := 0; i < 3; i++ {
+				if got := lookup("x"); got.IsPresent() {
+					t.Errorf("lookup(x) = %v, want empty", got)
+				}
+				lookup("7")
+			}
+			if n := c.count("x"); n != tt.calls {
+				t.Errorf("fn called %d times for an empty result, want %d", n, tt.calls)
+			}
+			if n := c.count("7"); n != 1 {
+				t.Errorf("fn called %d times for a present result, want 1", n)
+			}
+		})
+	}
+}
+
+func TestMemoizeMaxSize(t *testing.T) {
+	var c counter
+	lookup := Memoize(c.wrap(parse), WithMaxSize(2))
+	lookup("1")
+	lookup("2")
+	lookup("1") // 1 is now the most recently used
+	lookup("3") // evicts 2
+	lookup("1")
+	lookup("3")
+	if n := c.count("1"); n != 1 {
+		t.Errorf("fn called %d times for the recently used key, want 1", n)
+	}
+	if n := c.count("3"); n != 1 {
+		t.Errorf("fn called %d times for the newest key, want 1", n)
+	}
+	if got := lookup("2"); got != Of(2) {
+		t.Errorf("lookup(2) = %v, want Optional[2]", got)
+	}
+	if n := c.count("2"); n != 2 {
+		t.Errorf("fn called %d times for the evicted key, want 2", n)
+	}
+
+	var unbounded counter
+	lookup = Memoize(unbounded.wrap(parse), WithMaxSize(0))
+	for i := 0; i < 100; i++ {
+		lookup(strconv.Itoa(i))
+	}
+	lookup("0")
+	if n := unbounded.count("0"); n != 1 {
+		t.Errorf("WithMaxSize(0) evicted: fn called %d times, want 1", n)
+	}
+}
+
+func TestMemoizePanic(t *testing.T) {
+	var c counter
+	fail := true
+	lookup := Memoize(c.wrap(func(key string) Optional[int] {
+		if fail {
+			fail = false
+			panic("transient")
+		}
+		return parse(key)
+	}))
+	func() {
+		defer func() {
+			if r := recover(); r != "transient" {
+				t.Errorf("recovered %v, want the panic of fn", r)
+			}
+		}()
+		lookup("5")
+	}()
+	if got := lookup("5"); got != Of(5) {
+		t.Errorf("lookup after panic = %v, want Optional[5]", got)
+	}
+	if n := c.count("5"); n != 2 {
+		t.Errorf("fn called %d times, want 2: the panic must not be cached", n)
+	}
+}
@@ -0,0 +1,92 @@
+package optional
+
+import (
+	"context"
+	"sync"
+)
+
+// Observable holds an Optional that may change over time, such as a feature
+// flag that may be unset, and notifies subscribers of every change. It is safe
+// for concurrent use. The zero value is an empty Observable ready to use; an
+// Observable must not be copied after first use.
+type Observable[T any] struct {
+	mu   sync.Mutex
+	opt  Optional[T]
+	subs map[chan Optional[T]]struct{}
+}
+
+// Load returns the current state.
+func (o *Observable[T]) Load() Optional[T] {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.opt
+}
+
+// Set stores v and notifies subscribers, even if v equals the current value.
+func (o *Observable[T]) Set(v T) {
+	o.store(Of(v))
+}
+
+// Clear makes the state empty and notifies subscribers.
+func (o *Observable[T]) Clear() {
+	o.store(Empty[T]())
+}
+
+func (o *Observable[T]) store(opt Optional[T]) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.opt = opt
+	for ch := range o.subs {
+		deliver(ch, opt)
+	}
+}
+
+// Subscribe returns a channel that receives the current state immediately and
+// then the state after every Set and Clear, until ctx is done, at which point
+// the channel is closed.
+//
+// Delivery never blocks the publisher. Each channel buffers a single state,
+// and a state that has not been received yet is replaced by the newer one, so
+// a slow consumer skips intermediate states but always ends up seeing the
+// latest.
+//
+// Example:
+//
+//	for flag := range flags.Subscribe(ctx) {
+//	    limiter.SetEnabled(flag.OrElse(false))
+//	}
+func (o *Observable[T]) Subscribe(ctx context.Context) <-chan Optional[T] {
+	ch := make(chan Optional[T], 1)
+	o.mu.Lock()
+	if o.subs == nil {
+		o.subs = make(map[chan Optional[T]]struct{})
+	}
+	o.subs[ch] = struct{}{}
+	ch <- o.opt
+	o.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		o.mu.Lock()
+		delete(o.subs, ch)
+		close(ch)
+		o.mu.Unlock()
+	}()
+	return ch
+}
+
+// deliver sends opt on ch, replacing a state the subscriber has not received
+// yet. It must be called with the Observable locked, which makes it the only
+// sender on ch.
+func deliver[T any](ch chan Optional[T], opt Optional[T]) {
+	select {
+	case ch <- opt:
+		return
+	default:
+	}
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- opt
+}
@@ -0,0 +1,147 @@
+package optional
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestObservableSubscribe(t *testing.T) {
+	var o Observable[string]
+	if got := o.Load(); got.IsPresent() {
+		t.Fatalf("zero Observable Load = %v, want empty", got)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := o.Subscribe(ctx)
+	if got := receive(t, ch); got.IsPresent() {
+		t.Errorf("initial state = %v, want empty", got)
+	}
+
+	o.Set("on")
+	if got := receive(t, ch); got != Of("on") {
+		t.Errorf("after Set = %v, want Optional[on]", got)
+	}
+	o.Set("on")
+	if got := receive(t, ch); got != Of("on") {
+		t.Errorf("after repeated Set = %v, want Optional[on]", got)
+	}
+	o.Clear()
+	if got := receive(t, ch); got.IsPresent() {
+		t.Errorf("after Clear = %v, want empty", got)
+	}
+	if got := o.Load(); got.IsPresent() {
+		t.Errorf("Load after Clear = %v, want empty", got)
+	}
+
+	late := o.Subscribe(ctx)
+	if got := receive(t, late); got.IsPresent() {
+		t.Errorf("late subscriber's initial state = %v, want empty", got)
+	}
+	o.Set("again")
+	if got := receive(t, late); got != Of("again") {
+		t.Errorf("late subscriber after Set = %v, want Optional[again]", got)
+	}
+}
+
+// A consumer that does not keep up never blocks Set and sees the latest state
+// once it catches up.
+func TestObservableSlowConsumer(t *testing.T) {
+	var o Observable[int]
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := o.Subscribe(ctx)
+	receive(t, ch)
+
+	for i := 1; i <= 1000; i++ {
+		o.Set(i)
+	}
+	if got := receive(t, ch); got != Of(1000) {
+		t.Errorf("slow consumer received %v, want Optional[1000]", got)
+	}
+	select {
+	case got := <-ch:
+		t.Errorf("received stale state %v after the latest", got)
+	default:
+	}
+}
+
+// With a publisher and several consumers running concurrently, each consumer
+// sees states in publication order and ends with the last one.
+func TestObservableConcurrent(t *testing.T) {
+	const n = 2000
+	var o Observable[int]
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for c := 0; c < 4; c++ {
+		ch := o.Subscribe(ctx)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			last := 0
+			for opt := range ch {
+				v := opt.OrElse(0)
+				if v < last {
+					t.Errorf("received %d after %d", v, last)
+				}
+				if last = v; last == n {
+					return
+				}
+			}
+			t.Error("channel closed before the last state")
+		}()
+	}
+	for i := 1; i <= n; i++ {
+		o.Set(i)
+	}
+	wg.Wait()
+}
+
+func TestObservableUnsubscribe(t *testing.T) {
+	var o Observable[int]
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := o.Subscribe(ctx)
+	stay := o.Subscribe(context.Background())
+	receive(t, stay)
+
+	cancel()
+	deadline := time.After(time.Second)
+	for closed := false; !closed; {
+		select {
+		case _, ok := <-ch:
+			closed = !ok
+		case <-deadline:
+			t.Fatal("channel not closed after ctx was cancelled")
+		}
+	}
+
+	// Publishing after the close must neither panic nor reach the cancelled
+	// subscriber, and must still reach the others.
+	o.Set(1)
+	if got := receive(t, stay); got != Of(1) {
+		t.Errorf("remaining subscriber received %v, want Optional[1]", got)
+	}
+	o.mu.Lock()
+	subs := len(o.subs)
+	o.mu.Unlock()
+	if subs != 1 {
+		t.Errorf("%d subscribers registered, want 1", subs)
+	}
+}
+
+func receive[T any](t *testing.T, ch <-chan Optional[T]) Optional[T] {
+	t.Helper()
+	select {
+	case o, ok := <-ch:
+		if !ok {
+			t.Fatal("channel closed")
+		}
+		return o
+	case <-time.After(time.Second):
+		t.Fatal("no state received")
+	}
+	return Empty[T]()
+}
@@ -0,0 +1,104 @@
+// Package optcmp provides an Optional restricted to comparable types, so that
+// the == operator means value equality. It is intended for use as a map key or
+// in switch statements, where two independently constructed Optionals holding
+// the same value must be indistinguishable.
+//
+// Example usage:
+//
+//	buckets := map[optcmp.Optional[string]]int{}
+//	buckets[optcmp.Of("tenant-a")]++
+//	buckets[optcmp.Of("tenant-a")]++ // same key
+//	buckets[optcmp.Empty[string]()]++
+package optcmp
+
+import (
+	"fmt"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// Optional is a container object which may or may not contain a comparable value.
+// Two Optionals are == if both are empty, or if both are present and their values
+// are ==. The zero value is an empty Optional.
+type Optional[T comparable] struct {
+	value   T
+	present bool
+}
+
+// Of returns an Optional containing the given value.
+//
+// Example:
+//
+//	opt := optcmp.Of("hello")
+func Of[T comparable](val T) Optional[T] {
+	return Optional[T]{value: val, present: true}
+}
+
+// Empty returns an empty Optional instance.
+//
+// Example:
+//
+//	opt := optcmp.Empty[string]()
+func Empty[T comparable]() Optional[T] {
+	return Optional[T]{}
+}
+
+// FromOptional converts an optional.Optional into a comparable Optional,
+// preserving presence and value.
+//
+// Example:
+//
+//	key := optcmp.FromOptional(optional.Of("tenant-a"))
+func FromOptional[T comparable](o optional.Optional[T]) Optional[T] {
+	if val, ok := o.Get(); ok {
+		return Of(val)
+	}
+	return Empty[T]()
+}
+
+// ToOptional converts the comparable Optional back into an optional.Optional,
+// preserving presence and value.
+//
+// Example:
+//
+//	opt := optcmp.Of("tenant-a").ToOptional()
+func (o Optional[T]) ToOptional() optional.Optional[T] {
+	if o.present {
+		return optional.Of(o.value)
+	}
+	return optional.Empty[T]()
+}
+
+// IsPresent returns true if a value is present, otherwise false.
+func (o Optional[T]) IsPresent() bool {
+	return o.present
+}
+
+// IsEmpty returns true if no value is present, otherwise false.
+func (o Optional[T]) IsEmpty() bool {
+	return !o.present
+}
+
+// Get returns the value if present, along with a boolean indicating whether
+// the value was present. If no value is present, returns the zero value for type T
+// and false.
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.present
+}
+
+// OrElse returns the value if present, otherwise returns the provided default value.
+func (o Optional[T]) OrElse(other T) T {
+	if o.present {
+		return o.value
+	}
+	return other
+}
+
+// String returns a string representation of the Optional, formatted the same
+// way as optional.Optional: "Optional[value]" or "Optional.empty".
+func (o Optional[T]) String() string {
+	if o.present {
+		return fmt.Sprintf("Optional[%v]", o.value)
+	}
+	return "Optional.empty"
+}
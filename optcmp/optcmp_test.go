@@ -0,0 +1,96 @@
+package optcmp
+
+import (
+	"testing"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+func TestMapKey(t *testing.T) {
+	counts := map[Optional[string]]int{}
+	counts[Of("tenant-a")]++
+	counts[Of(string([]byte("tenant-a")))]++
+	counts[FromOptional(optional.Of("tenant-a"))]++
+	counts[Of("tenant-b")]++
+	counts[Empty[string]()]++
+	counts[Optional[string]{}]++
+	counts[FromOptional(optional.Empty[string]())]++
+	counts[Of("")]++
+
+	want := map[Optional[string]]int{
+		Of("tenant-a"):  3,
+		Of("tenant-b"):  1,
+		Empty[string](): 3,
+		Of(""):          1,
+	}
+	if len(counts) != len(want) {
+		t.Fatalf("got %d keys %v, want %d", len(counts), counts, len(want))
+	}
+	for k, n := range want {
+		if counts[k] != n {
+			t.Errorf("counts[%v] = %d, want %d", k, counts[k], n)
+		}
+	}
+}
+
+func TestEquality(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Optional[int]
+		want bool
+	}{
+		{"same value", Of(42), Of(42), true},
+		{"different values", Of(42), Of(43), false},
+		{"both empty", Empty[int](), Optional[int]{}, true},
+		{"present zero vs empty", Of(0), Empty[int](), false},
+		{"round trip", FromOptional(Of(7).ToOptional()), Of(7), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a == tt.b; got != tt.want {
+				t.Errorf("%v == %v is %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSwitch(t *testing.T) {
+	classify := func(o Optional[string]) string {
+		switch o {
+		case Of("admin"):
+			return "admin"
+		case Empty[string]():
+			return "anonymous"
+		default:
+			return "user"
+		}
+	}
+	for in, want := range map[Optional[string]]string{
+		Of("admin"):     "admin",
+		Empty[string](): "anonymous",
+		Of("ada"):       "user",
+	} {
+		if got := classify(in); got != want {
+			t.Errorf("classify(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestAccessors(t *testing.T) {
+	o := Of("x")
+	if v, ok := o.Get(); v != "x" || !ok {
+		t.Errorf("Get = %q, %v", v, ok)
+	}
+	if !o.IsPresent() || o.IsEmpty() {
+		t.Error("Of reports empty")
+	}
+	if got := Empty[string]().OrElse("def"); got != "def" {
+		t.Errorf("OrElse = %q", got)
+	}
+	if got, want := o.String(), optional.Of("x").String(); got != want {
+		t.Errorf("String = %q, want %q", got, want)
+	}
+	if got := o.ToOptional(); !got.Equals(optional.Of("x")) {
+		t.Errorf("ToOptional = %v", got)
+	}
+}
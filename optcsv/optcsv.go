@@ -0,0 +1,102 @@
+// Package optcsv provides an Optional that implements the MarshalCSV and
+// UnmarshalCSV methods used by gocsv and similar CSV mappers, so blank cells
+// can be modelled as absent values instead of zero values.
+//
+// An empty cell unmarshals to an empty Optional, and an empty Optional
+// marshals to an empty cell. Any other cell is parsed into T: strings are
+// taken verbatim, booleans and numbers are parsed with strconv, time.Duration
+// with time.ParseDuration, time.Time as RFC 3339, and any other type through
+// encoding.TextUnmarshaler. Marshaling is the inverse, using
+// encoding.TextMarshaler where T implements it.
+//
+// CSV cannot tell a blank cell from an empty quoted one: encoding/csv strips
+// the quotes before a mapper sees the cell. A present empty string therefore
+// marshals to an empty cell and reads back as an empty Optional. Use a
+// sentinel value in T if the distinction matters.
+//
+// Example usage:
+//
+//	type Row struct {
+//	    Name  string                     `csv:"name"`
+//	    Age   optcsv.Optional[int]       `csv:"age"`
+//	    Since optcsv.Optional[time.Time] `csv:"since"`
+//	}
+//	var rows []Row
+//	err := gocsv.UnmarshalFile(f, &rows)
+package optcsv
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+	"github.com/sergei-bronnikov/go-optional/internal/parse"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Optional is an optional.Optional with CSV marshaling methods. All Optional
+// methods are promoted, and the wrapped Optional is available as the Optional
+// field.
+type Optional[T any] struct {
+	optional.Optional[T]
+}
+
+// Wrap returns o as an optcsv.Optional.
+//
+// Example:
+//
+//	row.Age = optcsv.Wrap(optional.Of(42))
+func Wrap[T any](o optional.Optional[T]) Optional[T] {
+	return Optional[T]{Optional: o}
+}
+
+// MarshalCSV returns the cell for o: an empty string if o is empty, and the
+// formatted value otherwise.
+func (o Optional[T]) MarshalCSV() (string, error) {
+	val, ok := o.Get()
+	if !ok {
+		return "", nil
+	}
+	if m, ok := any(val).(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+	v := reflect.ValueOf(&val).Elem()
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String(), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("optcsv: cannot marshal %s", v.Type())
+}
+
+// UnmarshalCSV sets o from a cell: an empty cell makes o empty, and any other
+// cell is parsed into T.
+func (o *Optional[T]) UnmarshalCSV(cell string) error {
+	if cell == "" {
+		o.Optional = optional.Empty[T]()
+		return nil
+	}
+	var zero T
+	t := reflect.TypeOf(&zero).Elem()
+	v, err := parse.Value(cell, t, "")
+	if err != nil {
+		return fmt.Errorf("optcsv: cannot parse %q as %s: %w", cell, t, err)
+	}
+	o.Optional = optional.Of(v.Interface().(T))
+	return nil
+}
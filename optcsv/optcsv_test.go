@@ -0,0 +1,173 @@
+package optcsv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// row stands for a struct mapped by a CSV library: each column is one
+// optcsv.Optional.
+type row struct {
+	Name   Optional[string]
+	Age    Optional[int]
+	Since  Optional[time.Time]
+	Active Optional[bool]
+	Ratio  Optional[float64]
+	TTL    Optional[time.Duration]
+	Addr   Optional[netip.Addr]
+	Visits Optional[uint16]
+}
+
+func (r *row) cells() []interface {
+	MarshalCSV() (string, error)
+	UnmarshalCSV(string) error
+} {
+	return []interface {
+		MarshalCSV() (string, error)
+		UnmarshalCSV(string) error
+	}{&r.Name, &r.Age, &r.Since, &r.Active, &r.Ratio, &r.TTL, &r.Addr, &r.Visits}
+}
+
+func writeRows(t *testing.T, rows []row) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	for i := range rows {
+		var record []string
+		for _, c := range rows[i].cells() {
+			cell, err := c.MarshalCSV()
+			if err != nil {
+				t.Fatal(err)
+			}
+			record = append(record, cell)
+		}
+		if err := w.Write(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func readRows(data string) ([]row, error) {
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]row, len(records))
+	for i, record := range records {
+		for j, c := range rows[i].cells() {
+			if err := c.UnmarshalCSV(record[j]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rows, nil
+}
+
+func TestRoundTrip(t *testing.T) {
+	since := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.FixedZone("CEST", 2*60*60))
+	rows := []row{
+		{
+			Name: Wrap(optional.Of("Ada, \"the first\"")), Age: Wrap(optional.Of(36)),
+			Since: Wrap(optional.Of(since)), Active: Wrap(optional.Of(false)),
+			Ratio: Wrap(optional.Of(0.25)), TTL: Wrap(optional.Of(90 * time.Second)),
+			Addr: Wrap(optional.Of(netip.MustParseAddr("10.0.0.1"))), Visits: Wrap(optional.Of(uint16(0))),
+		},
+		{},
+		{Age: Wrap(optional.Of(0)), Since: Wrap(optional.Of(time.Time{}))},
+	}
+	data := writeRows(t, rows)
+	want := `"Ada, ""the first""",36,2024-05-06T07:08:09.123456789+02:00,false,0.25,1m30s,10.0.0.1,0` + "\n" +
+		",,,,,,,\n" +
+		",0,0001-01-01T00:00:00Z,,,,,\n"
+	if data != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", data, want)
+	}
+
+	got, err := readRows(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range rows {
+		// time.Time values are compared with Equal, as the location is not
+		// preserved exactly.
+		gs, _ := got[i].Since.Get()
+		ws, _ := rows[i].Since.Get()
+		if !gs.Equal(ws) || got[i].Since.IsPresent() != rows[i].Since.IsPresent() {
+			t.Errorf("row %d: Since = %v, want %v", i, got[i].Since, rows[i].Since)
+		}
+		got[i].Since, rows[i].Since = Optional[time.Time]{}, Optional[time.Time]{}
+		if !reflect.DeepEqual(got[i], rows[i]) {
+			t.Errorf("row %d = %+v, want %+v", i, got[i], rows[i])
+		}
+	}
+}
+
+func TestEmptyCell(t *testing.T) {
+	o := Wrap(optional.Of(7))
+	if err := o.UnmarshalCSV(""); err != nil {
+		t.Fatal(err)
+	}
+	if o.IsPresent() {
+		t.Errorf("UnmarshalCSV(\"\") = %v, want empty", o.Optional)
+	}
+	// A present empty string cannot survive the round trip.
+	s := Wrap(optional.Of(""))
+	cell, _ := s.MarshalCSV()
+	if err := s.UnmarshalCSV(cell); err != nil || s.IsPresent() {
+		t.Errorf("present empty string read back as %v, %v, want empty", s.Optional, err)
+	}
+}
+
+func TestBadCell(t *testing.T) {
+	tests := []struct {
+		name    string
+		cell    string
+		target  interface{ UnmarshalCSV(string) error }
+		wantErr string
+		is      error
+	}{
+		{"int", "abc", new(Optional[int]), `optcsv: cannot parse "abc" as int`, strconv.ErrSyntax},
+		{"int overflow", "300", new(Optional[uint8]), `optcsv: cannot parse "300" as uint8`, strconv.ErrRange},
+		{"bool", "maybe", new(Optional[bool]), `optcsv: cannot parse "maybe" as bool`, strconv.ErrSyntax},
+		{"time", "yesterday", new(Optional[time.Time]), `optcsv: cannot parse "yesterday" as time.Time`, nil},
+		{"duration", "soon", new(Optional[time.Duration]), `optcsv: cannot parse "soon" as time.Duration`, nil},
+		{"text", "not-an-ip", new(Optional[netip.Addr]), `optcsv: cannot parse "not-an-ip" as netip.Addr`, nil},
+		{"unsupported", "x", new(Optional[[]string]), "unsupported type []string", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.target.UnmarshalCSV(tt.cell)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("UnmarshalCSV(%q) error = %v, want one containing %q", tt.cell, err, tt.wantErr)
+			}
+			if tt.is != nil && !errors.Is(err, tt.is) {
+				t.Errorf("error = %v, want one wrapping %v", err, tt.is)
+			}
+		})
+	}
+
+	if _, err := readRows("x,abc,,,,,,\n"); err == nil {
+		t.Error("reading a row with a bad age succeeded")
+	}
+}
+
+func TestMarshalUnsupported(t *testing.T) {
+	o := Wrap(optional.Of([]string{"a"}))
+	if _, err := o.MarshalCSV(); err == nil || err.Error() != "optcsv: cannot marshal []string" {
+		t.Errorf("MarshalCSV error = %v", err)
+	}
+}
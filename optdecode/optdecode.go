@@ -0,0 +1,204 @@
+// Package optdecode populates structs with Optional fields from flat string
+// maps, such as HTTP form values, CSV rows or feature-flag payloads. A key that
+// is present is parsed into the field's element type and wrapped with
+// optional.Of; a key that is absent leaves the field untouched, so Optional
+// fields stay empty.
+//
+// Example usage:
+//
+//	type Filter struct {
+//	    Query optional.Optional[string]    `opt:"q"`
+//	    Limit optional.Optional[int]       `opt:"limit"`
+//	    Since optional.Optional[time.Time] `opt:"since" layout:"2006-01-02"`
+//	    Tier  optional.Optional[Tier]      // key "Tier", parsed with UnmarshalText
+//	    Page  struct {
+//	        Size optional.Optional[int] `opt:"size"` // key "page.size"
+//	    } `opt:"page"`
+//	}
+//
+//	var f Filter
+//	err := optdecode.DecodeStringMap(map[string]string{"q": "go", "page.size": "20"}, &f)
+package optdecode
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+	"github.com/sergei-bronnikov/go-optional/internal/parse"
+)
+
+// ParseError is returned when the value of a key cannot be parsed into the type
+// of its target field.
+type ParseError struct {
+	Key   string
+	Value string
+	Type  reflect.Type
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("optdecode: key %q: cannot parse %q as %s: %v", e.Key, e.Value, e.Type, e.Err)
+}
+
+// Unwrap returns the underlying parse error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// UnknownKeysError is returned in strict mode when the source map contains
+// keys that do not match any field. Keys are sorted.
+type UnknownKeysError struct {
+	Keys []string
+}
+
+// Error implements the error interface.
+func (e *UnknownKeysError) Error() string {
+	return "optdecode: unknown keys: " + strings.Join(e.Keys, ", ")
+}
+
+// Option configures DecodeStringMap.
+type Option func(*decoder)
+
+// WithUnknownKeys stores the sorted list of source keys that did not match any
+// field in dst.
+func WithUnknownKeys(dst *[]string) Option {
+	return func(d *decoder) {
+		d.unknownDst = dst
+	}
+}
+
+// Strict makes DecodeStringMap return an *UnknownKeysError if the source map
+// contains keys that do not match any field.
+func Strict() Option {
+	return func(d *decoder) {
+		d.strict = true
+	}
+}
+
+type decoder struct {
+	src        map[string]string
+	used       map[string]bool
+	unknownDst *[]string
+	strict     bool
+}
+
+// DecodeStringMap decodes src into the struct pointed to by dest.
+//
+// Each exported field is matched with the key given by its `opt` struct tag,
+// or with its field name if there is no tag; a tag of "-" skips the field.
+// Nested struct fields are matched with dotted keys prefixed by the field's
+// key, and embedded structs are flattened into their parent.
+//
+// Optional fields are left untouched when their key is absent. Optional and
+// plain fields of strings, booleans, integers, floats, time.Duration, time.Time
+// and types implementing encoding.TextUnmarshaler are supported. time.Time
+// fields are parsed with the layout given by the `layout` struct tag, or
+// time.RFC3339 by default. A value that cannot be parsed produces a
+// *ParseError naming the key and the target type.
+func DecodeStringMap(src map[string]string, dest any, opts ...Option) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("optdecode: dest must be a non-nil pointer to a struct")
+	}
+	d := &decoder{src: src, used: make(map[string]bool, len(src))}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if err := d.decodeStruct(rv.Elem(), ""); err != nil {
+		return err
+	}
+
+	var unknown []string
+	for key := range src {
+		if !d.used[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	if d.unknownDst != nil {
+		*d.unknownDst = unknown
+	}
+	if d.strict && len(unknown) > 0 {
+		return &UnknownKeysError{Keys: unknown}
+	}
+	return nil
+}
+
+func (d *decoder) decodeStruct(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		tag := field.Tag.Get("opt")
+		if tag == "-" {
+			continue
+		}
+		fv := v.Field(i)
+
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct && !isLeaf(field.Type) {
+			if err := d.decodeStruct(fv, prefix); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		key := tag
+		if key == "" {
+			key = field.Name
+		}
+		key = prefix + key
+
+		if !isLeaf(field.Type) && field.Type.Kind() == reflect.Struct {
+			if err := d.decodeStruct(fv, key+"."); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := d.decodeField(fv, field, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *decoder) decodeField(fv reflect.Value, field reflect.StructField, key string) error {
+	s, ok := d.src[key]
+	if !ok {
+		return nil
+	}
+	d.used[key] = true
+
+	target := field.Type
+	elem, isOptional := optional.ElemType(target)
+	if isOptional {
+		target = elem
+	}
+	val, err := parse.Value(s, target, field.Tag.Get("layout"))
+	if err != nil {
+		return &ParseError{Key: key, Value: s, Type: target, Err: err}
+	}
+	if isOptional {
+		val = optional.ReflectOf(field.Type, val)
+	}
+	fv.Set(val)
+	return nil
+}
+
+// isLeaf reports whether fields of type t are decoded from a single key rather
+// than recursed into.
+func isLeaf(t reflect.Type) bool {
+	if _, ok := optional.ElemType(t); ok {
+		return true
+	}
+	return parse.Supported(t)
+}
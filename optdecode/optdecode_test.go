@@ -0,0 +1,218 @@
+package optdecode
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+type tier int
+
+const (
+	free tier = iota + 1
+	pro
+)
+
+func (t *tier) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "free":
+		*t = free
+	case "pro":
+		*t = pro
+	default:
+		return fmt.Errorf("unknown tier %q", b)
+	}
+	return nil
+}
+
+type Paging struct {
+	Cursor optional.Optional[string] `opt:"cursor"`
+}
+
+type filter struct {
+	Paging
+	Query   optional.Optional[string]        `opt:"q"`
+	Limit   optional.Optional[int]           `opt:"limit"`
+	Since   optional.Optional[time.Time]     `opt:"since" layout:"2006-01-02"`
+	Until   optional.Optional[time.Time]     `opt:"until"`
+	Timeout optional.Optional[time.Duration] `opt:"timeout"`
+	Tier    optional.Optional[tier]
+	Plan    tier   `opt:"plan"`
+	Verbose bool   `opt:"v"`
+	Skip    string `opt:"-"`
+	hidden  string
+	Page    struct {
+		Size  optional.Optional[int] `opt:"size"`
+		Order struct {
+			Desc optional.Optional[bool] `opt:"desc"`
+		} `opt:"order"`
+	} `opt:"page"`
+	Sort struct {
+		By optional.Optional[string]
+	}
+}
+
+func TestDecodeStringMap(t *testing.T) {
+	src := map[string]string{
+		"cursor":          "abc",
+		"q":               "",
+		"limit":           "20",
+		"since":           "2024-01-02",
+		"until":           "2024-02-03T04:05:06Z",
+		"timeout":         "250ms",
+		"Tier":            "pro",
+		"plan":            "free",
+		"v":               "true",
+		"page.size":       "50",
+		"page.order.desc": "1",
+		"Sort.By":         "name",
+	}
+	var f filter
+	if err := DecodeStringMap(src, &f, Strict()); err != nil {
+		t.Fatal(err)
+	}
+
+	var want filter
+	want.Cursor = optional.Of("abc")
+	want.Query = optional.Of("")
+	want.Limit = optional.Of(20)
+	want.Since = optional.Of(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	want.Until = optional.Of(time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC))
+	want.Timeout = optional.Of(250 * time.Millisecond)
+	want.Tier = optional.Of(pro)
+	want.Plan = free
+	want.Verbose = true
+	want.Page.Size = optional.Of(50)
+	want.Page.Order.Desc = optional.Of(true)
+	want.Sort.By = optional.Of("name")
+	if !reflect.DeepEqual(f, want) {
+		t.Errorf("DecodeStringMap =\n%+v\nwant\n%+v", f, want)
+	}
+}
+
+func TestDecodeStringMapAbsentKeys(t *testing.T) {
+	f := filter{Limit: optional.Of(10), Plan: pro}
+	if err := DecodeStringMap(map[string]string{"q": "go"}, &f); err != nil {
+		t.Fatal(err)
+	}
+	if f.Limit != optional.Of(10) || f.Plan != pro || f.Page.Size.IsPresent() || f.Cursor.IsPresent() {
+		t.Errorf("absent keys changed the fields: %+v", f)
+	}
+	if err := DecodeStringMap(nil, &f); err != nil {
+		t.Errorf("DecodeStringMap(nil) error = %v", err)
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	src := map[string]string{
+		"q":             "go",
+		"zz":            "1",
+		"page":          "2",
+		"page.size":     "3",
+		"page.nope":     "4",
+		"Skip":          "5",
+		"hidden":        "6",
+		"Paging.cursor": "7",
+	}
+	want := []string{"Paging.cursor", "Skip", "hidden", "page", "page.nope", "zz"}
+
+	var f filter
+	var unknown []string
+	if err := DecodeStringMap(src, &f, WithUnknownKeys(&unknown)); err != nil {
+		t.Fatalf("non-strict DecodeStringMap error = %v", err)
+	}
+	if !reflect.DeepEqual(unknown, want) {
+		t.Errorf("unknown keys = %q, want %q", unknown, want)
+	}
+	if f.Query != optional.Of("go") || f.Page.Size != optional.Of(3) {
+		t.Errorf("known keys not decoded: %+v", f)
+	}
+
+	unknown = []string{"stale"}
+	err := DecodeStringMap(src, &f, Strict(), WithUnknownKeys(&unknown))
+	var uerr *UnknownKeysError
+	if !errors.As(err, &uerr) || !reflect.DeepEqual(uerr.Keys, want) {
+		t.Fatalf("strict error = %v, want an *UnknownKeysError with %q", err, want)
+	}
+	if err.Error() != "optdecode: unknown keys: Paging.cursor, Skip, hidden, page, page.nope, zz" {
+		t.Errorf("Error() = %q", err)
+	}
+	if !reflect.DeepEqual(unknown, want) {
+		t.Errorf("WithUnknownKeys alongside Strict = %q, want %q", unknown, want)
+	}
+
+	unknown = []string{"stale"}
+	if err := DecodeStringMap(map[string]string{"q": "go"}, &f, Strict(), WithUnknownKeys(&unknown)); err != nil || unknown != nil {
+		t.Errorf("all keys known: error = %v, unknown = %q, want nil and nil", err, unknown)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     map[string]string
+		wantKey string
+		wantTyp reflect.Type
+		is      error
+	}{
+		{"int", map[string]string{"limit": "ten"}, "limit", reflect.TypeOf(0), strconv.ErrSyntax},
+		{"nested", map[string]string{"page.size": "99999999999999999999"}, "page.size", reflect.TypeOf(0), strconv.ErrRange},
+		{"plain bool", map[string]string{"v": "yes"}, "v", reflect.TypeOf(false), strconv.ErrSyntax},
+		{"layout", map[string]string{"since": "2024-01-02T00:00:00Z"}, "since", reflect.TypeOf(time.Time{}), nil},
+		{"default layout", map[string]string{"until": "2024-01-02"}, "until", reflect.TypeOf(time.Time{}), nil},
+		{"text", map[string]string{"Tier": "gold"}, "Tier", reflect.TypeOf(tier(0)), nil},
+		{"embedded", map[string]string{"cursor": "ok", "timeout": "soon"}, "timeout", reflect.TypeOf(time.Duration(0)), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f filter
+			err := DecodeStringMap(tt.src, &f)
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("error = %v, want a *ParseError", err)
+			}
+			if perr.Key != tt.wantKey || perr.Value != tt.src[tt.wantKey] || perr.Type != tt.wantTyp {
+				t.Errorf("ParseError = %+v, want Key %q, Value %q, Type %s", perr, tt.wantKey, tt.src[tt.wantKey], tt.wantTyp)
+			}
+			if perr.Unwrap() != perr.Err || errors.Unwrap(err) != perr.Err {
+				t.Errorf("Unwrap() = %v, want %v", perr.Unwrap(), perr.Err)
+			}
+			if tt.is != nil && !errors.Is(err, tt.is) {
+				t.Errorf("error = %v, want one wrapping %v", err, tt.is)
+			}
+		})
+	}
+
+	var f filter
+	err := DecodeStringMap(map[string]string{"limit": "ten"}, &f)
+	if want := `optdecode: key "limit": cannot parse "ten" as int: strconv.ParseInt: parsing "ten": invalid syntax`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err, want)
+	}
+}
+
+func TestUnsupportedField(t *testing.T) {
+	var dest struct {
+		Tags optional.Optional[[]string] `opt:"tags"`
+	}
+	err := DecodeStringMap(map[string]string{"tags": "a,b"}, &dest)
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Err.Error() != "unsupported type []string" {
+		t.Errorf("error = %v, want a *ParseError for the unsupported type", err)
+	}
+}
+
+func TestBadDest(t *testing.T) {
+	var f filter
+	var nilFilter *filter
+	for _, dest := range []any{f, nilFilter, new(string), nil} {
+		err := DecodeStringMap(map[string]string{"q": "go"}, dest)
+		if err == nil || err.Error() != "optdecode: dest must be a non-nil pointer to a struct" {
+			t.Errorf("DecodeStringMap(%T) error = %v", dest, err)
+		}
+	}
+}
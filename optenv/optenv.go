@@ -0,0 +1,203 @@
+// Package optenv loads structs from environment variables. Optional fields
+// stay empty when their variable is unset, so configuration code can tell an
+// unset variable from one set to a zero value.
+//
+// Example usage:
+//
+//	type Config struct {
+//	    Addr    string                           `env:"ADDR,required"`
+//	    Debug   optional.Optional[bool]          `env:"DEBUG"`
+//	    Timeout optional.Optional[time.Duration] `env:"TIMEOUT"`
+//	    Since   optional.Optional[time.Time]     `env:"SINCE" layout:"2006-01-02"`
+//	    DB      struct {
+//	        Host optional.Optional[string] `env:"HOST"` // MYAPP_DB_HOST
+//	    } `env:"DB_"`
+//	}
+//
+//	var cfg Config
+//	err := optenv.Load(&cfg, optenv.WithPrefix("MYAPP_"))
+package optenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+	"github.com/sergei-bronnikov/go-optional/internal/parse"
+)
+
+// ParseError is returned when the value of a variable cannot be parsed into the
+// type of its target field.
+type ParseError struct {
+	Var   string
+	Value string
+	Type  reflect.Type
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("optenv: variable %s: cannot parse %q as %s: %v", e.Var, e.Value, e.Type, e.Err)
+}
+
+// Unwrap returns the underlying parse error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// MissingVarsError is returned when required variables are unset. Vars lists
+// every missing variable, with the prefix, in field declaration order.
+type MissingVarsError struct {
+	Vars []string
+}
+
+// Error implements the error interface.
+func (e *MissingVarsError) Error() string {
+	return "optenv: missing required variables: " + strings.Join(e.Vars, ", ")
+}
+
+// Option configures Load.
+type Option func(*loader)
+
+// WithPrefix prepends prefix to every variable name.
+func WithPrefix(prefix string) Option {
+	return func(l *loader) {
+		l.prefix = prefix
+	}
+}
+
+// WithLookup replaces os.LookupEnv as the source of variables, for tests or
+// for loading from another key-value store.
+//
+// Example:
+//
+//	env := map[string]string{"ADDR": ":8080"}
+//	err := optenv.Load(&cfg, optenv.WithLookup(func(name string) (string, bool) {
+//	    v, ok := env[name]
+//	    return v, ok
+//	}))
+func WithLookup(lookup func(name string) (string, bool)) Option {
+	return func(l *loader) {
+		l.lookup = lookup
+	}
+}
+
+type loader struct {
+	prefix  string
+	lookup  func(string) (string, bool)
+	missing []string
+}
+
+// Load populates the struct pointed to by dest from environment variables.
+//
+// Each exported field with an `env` struct tag is read from the variable it
+// names; fields without the tag are skipped. A variable set to any value, the
+// empty string included, is parsed into the field's type, or into the element
+// type of an Optional field, which is then made present. Strings, booleans,
+// integers, floats, time.Duration, time.Time and types implementing
+// encoding.TextUnmarshaler are supported; time.Time uses the layout given by
+// the `layout` struct tag, or time.RFC3339 by default.
+//
+// An unset variable leaves its field untouched, unless the tag has the
+// required option, as in `env:"ADDR,required"`. Load then returns a
+// *MissingVarsError naming every missing variable at once. A value that cannot
+// be parsed produces a *ParseError.
+//
+// Nested struct fields other than Optionals and parsable types are loaded
+// recursively; their `env` tag, if any, is a prefix for their fields' names.
+// Embedded structs are flattened into their parent.
+func Load(dest any, opts ...Option) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("optenv: dest must be a non-nil pointer to a struct")
+	}
+	l := &loader{lookup: os.LookupEnv}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if err := l.loadStruct(rv.Elem(), l.prefix); err != nil {
+		return err
+	}
+	if len(l.missing) > 0 {
+		return &MissingVarsError{Vars: l.missing}
+	}
+	return nil
+}
+
+func (l *loader) loadStruct(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		tag, hasTag := field.Tag.Lookup("env")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fv := v.Field(i)
+
+		if !isLeaf(field.Type) && field.Type.Kind() == reflect.Struct {
+			if err := l.loadStruct(fv, prefix+name); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.PkgPath != "" || !hasTag || name == "" {
+			continue
+		}
+		if err := l.loadField(fv, field, prefix+name, hasOption(opts, "required")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *loader) loadField(fv reflect.Value, field reflect.StructField, name string, required bool) error {
+	s, ok := l.lookup(name)
+	if !ok {
+		if required {
+			l.missing = append(l.missing, name)
+		}
+		return nil
+	}
+
+	target := field.Type
+	elem, isOptional := optional.ElemType(target)
+	if isOptional {
+		target = elem
+	}
+	val, err := parse.Value(s, target, field.Tag.Get("layout"))
+	if err != nil {
+		return &ParseError{Var: name, Value: s, Type: target, Err: err}
+	}
+	if isOptional {
+		val = optional.ReflectOf(field.Type, val)
+	}
+	fv.Set(val)
+	return nil
+}
+
+func hasOption(opts, name string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isLeaf reports whether fields of type t are loaded from a single variable
+// rather than recursed into.
+func isLeaf(t reflect.Type) bool {
+	if _, ok := optional.ElemType(t); ok {
+		return true
+	}
+	return parse.Supported(t)
+}
@@ -0,0 +1,184 @@
+package optenv
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+func env(vars map[string]string) Option {
+	return WithLookup(func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	})
+}
+
+type Common struct {
+	Region optional.Optional[string] `env:"REGION"`
+}
+
+type config struct {
+	Common
+	Addr     string                           `env:"ADDR,required"`
+	Debug    optional.Optional[bool]          `env:"DEBUG"`
+	Timeout  optional.Optional[time.Duration] `env:"TIMEOUT"`
+	Since    optional.Optional[time.Time]     `env:"SINCE" layout:"2006-01-02"`
+	Seen     optional.Optional[time.Time]     `env:"SEEN"`
+	Bind     optional.Optional[netip.Addr]    `env:"BIND"`
+	Workers  int                              `env:"WORKERS"`
+	Name     optional.Optional[string]        `env:"NAME"`
+	Skipped  optional.Optional[string]        `env:"-"`
+	Untagged optional.Optional[string]
+	hidden   optional.Optional[string] `env:"HIDDEN"`
+	DB       struct {
+		Host optional.Optional[string] `env:"HOST,required"`
+		Port optional.Optional[uint16] `env:"PORT"`
+	} `env:"DB_"`
+	Cache struct {
+		Size optional.Optional[int] `env:"CACHE_SIZE"`
+	}
+}
+
+func TestLoad(t *testing.T) {
+	vars := map[string]string{
+		"APP_REGION":     "eu",
+		"APP_ADDR":       ":8080",
+		"APP_DEBUG":      "false",
+		"APP_TIMEOUT":    "1m30s",
+		"APP_SINCE":      "2024-01-02",
+		"APP_SEEN":       "2024-01-02T15:04:05Z",
+		"APP_BIND":       "10.0.0.1",
+		"APP_WORKERS":    "4",
+		"APP_NAME":       "",
+		"APP_HIDDEN":     "x",
+		"APP_-":          "x",
+		"APP_Untagged":   "x",
+		"APP_DB_HOST":    "db.local",
+		"APP_DB_PORT":    "5432",
+		"APP_CACHE_SIZE": "128",
+		"ADDR":           "unprefixed",
+	}
+	var cfg config
+	if err := Load(&cfg, WithPrefix("APP_"), env(vars)); err != nil {
+		t.Fatal(err)
+	}
+
+	var want config
+	want.Region = optional.Of("eu")
+	want.Addr = ":8080"
+	want.Debug = optional.Of(false)
+	want.Timeout = optional.Of(90 * time.Second)
+	want.Since = optional.Of(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	want.Seen = optional.Of(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC))
+	want.Bind = optional.Of(netip.MustParseAddr("10.0.0.1"))
+	want.Workers = 4
+	want.Name = optional.Of("")
+	want.DB.Host = optional.Of("db.local")
+	want.DB.Port = optional.Of(uint16(5432))
+	want.Cache.Size = optional.Of(128)
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load =\n%+v\nwant\n%+v", cfg, want)
+	}
+}
+
+func TestLoadUnsetLeavesField(t *testing.T) {
+	cfg := config{Addr: "default", Workers: 2, Debug: optional.Of(true)}
+	if err := Load(&cfg, env(map[string]string{"DB_HOST": "h"})); err == nil {
+		t.Fatal("Load succeeded without the required ADDR")
+	}
+	if cfg.Workers != 2 || cfg.Debug != optional.Of(true) || cfg.Region.IsPresent() || cfg.Timeout.IsPresent() {
+		t.Errorf("unset variables changed the fields: %+v", cfg)
+	}
+	if cfg.DB.Host != optional.Of("h") {
+		t.Errorf("DB.Host = %v, want Optional[h]", cfg.DB.Host)
+	}
+}
+
+func TestLoadMissingVars(t *testing.T) {
+	var cfg config
+	err := Load(&cfg, WithPrefix("APP_"), env(map[string]string{"APP_WORKERS": "1"}))
+	var missing *MissingVarsError
+	if !errors.As(err, &missing) {
+		t.Fatalf("Load error = %v, want a *MissingVarsError", err)
+	}
+	if want := []string{"APP_ADDR", "APP_DB_HOST"}; !reflect.DeepEqual(missing.Vars, want) {
+		t.Errorf("Vars = %q, want %q", missing.Vars, want)
+	}
+	if want := "optenv: missing required variables: APP_ADDR, APP_DB_HOST"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err, want)
+	}
+	if cfg.Workers != 1 {
+		t.Errorf("Workers = %d, want the other fields loaded regardless", cfg.Workers)
+	}
+}
+
+func TestLoadParseError(t *testing.T) {
+	tests := []struct {
+		name    string
+		vars    map[string]string
+		wantVar string
+		wantTyp reflect.Type
+		is      error
+		wantMsg string
+	}{
+		{
+			name: "int syntax", vars: map[string]string{"ADDR": "a", "DB_HOST": "h", "WORKERS": "four"},
+			wantVar: "WORKERS", wantTyp: reflect.TypeOf(0), is: strconv.ErrSyntax,
+			wantMsg: `optenv: variable WORKERS: cannot parse "four" as int: strconv.ParseInt: parsing "four": invalid syntax`,
+		},
+		{
+			name: "uint range", vars: map[string]string{"ADDR": "a", "DB_HOST": "h", "DB_PORT": "70000"},
+			wantVar: "DB_PORT", wantTyp: reflect.TypeOf(uint16(0)), is: strconv.ErrRange,
+		},
+		{
+			name: "bool", vars: map[string]string{"ADDR": "a", "DB_HOST": "h", "DEBUG": "yes"},
+			wantVar: "DEBUG", wantTyp: reflect.TypeOf(false), is: strconv.ErrSyntax,
+		},
+		{
+			name: "layout", vars: map[string]string{"ADDR": "a", "DB_HOST": "h", "SINCE": "02.01.2024"},
+			wantVar: "SINCE", wantTyp: reflect.TypeOf(time.Time{}),
+		},
+		{
+			name: "text", vars: map[string]string{"ADDR": "a", "DB_HOST": "h", "BIND": "nowhere"},
+			wantVar: "BIND", wantTyp: reflect.TypeOf(netip.Addr{}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg config
+			err := Load(&cfg, env(tt.vars))
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("Load error = %v, want a *ParseError", err)
+			}
+			if perr.Var != tt.wantVar || perr.Value != tt.vars[tt.wantVar] || perr.Type != tt.wantTyp {
+				t.Errorf("ParseError = %+v, want Var %s, Value %q, Type %s", perr, tt.wantVar, tt.vars[tt.wantVar], tt.wantTyp)
+			}
+			if errors.Unwrap(err) != perr.Err {
+				t.Errorf("Unwrap() = %v, want %v", errors.Unwrap(err), perr.Err)
+			}
+			var numErr *strconv.NumError
+			if tt.is != nil && (!errors.Is(err, tt.is) || !errors.As(err, &numErr)) {
+				t.Errorf("error = %v, want one wrapping a *strconv.NumError with %v", err, tt.is)
+			}
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Errorf("Error() = %q, want %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestLoadBadDest(t *testing.T) {
+	var cfg config
+	var nilCfg *config
+	for _, dest := range []any{cfg, nilCfg, new(int), nil} {
+		if err := Load(dest, env(nil)); err == nil || err.Error() != "optenv: dest must be a non-nil pointer to a struct" {
+			t.Errorf("Load(%T) error = %v", dest, err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Package optgql provides gqlgen marshalers for Optionals, so nullable GraphQL
+// fields can be bound to Optional values instead of pointers. Empty Optionals
+// are written as null, and both a null and an omitted input produce an empty
+// Optional.
+//
+// gqlgen requires the marshaling methods on the bound type itself, so each
+// supported scalar has a wrapper type embedding the corresponding Optional:
+// String, Int, Float, Boolean, Time and Any. All Optional methods are promoted,
+// and the wrapped Optional is available as the Optional field.
+//
+// To use them, list the wrapper as an additional model for the scalar in
+// gqlgen.yml; gqlgen then picks the model matching each field's Go type:
+//
+//	models:
+//	  String:
+//	    model:
+//	      - github.com/99designs/gqlgen/graphql.String
+//	      - github.com/sergei-bronnikov/go-optional/optgql.String
+//	  Int:
+//	    model:
+//	      - github.com/99designs/gqlgen/graphql.Int
+//	      - github.com/sergei-bronnikov/go-optional/optgql.Int
+//
+// and declare model fields with the wrapper types:
+//
+//	type User struct {
+//	    Nickname optgql.String `json:"nickname"`
+//	}
+//
+//	user.Nickname = optgql.String{Optional: optional.Of("neo")}
+package optgql
+
+import (
+	"io"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// String is an Optional[string] bound to a GraphQL String.
+type String struct {
+	optional.Optional[string]
+}
+
+// MarshalGQL implements graphql.Marshaler, writing null when empty.
+func (o String) MarshalGQL(w io.Writer) {
+	marshal(w, o.Optional, graphql.MarshalString)
+}
+
+// UnmarshalGQL implements graphql.Unmarshaler. A nil input produces an empty value.
+func (o *String) UnmarshalGQL(v any) (err error) {
+	o.Optional, err = unmarshal(v, graphql.UnmarshalString)
+	return err
+}
+
+// Int is an Optional[int] bound to a GraphQL Int.
+type Int struct {
+	optional.Optional[int]
+}
+
+// MarshalGQL implements graphql.Marshaler, writing null when empty.
+func (o Int) MarshalGQL(w io.Writer) {
+	marshal(w, o.Optional, graphql.MarshalInt)
+}
+
+// UnmarshalGQL implements graphql.Unmarshaler. A nil input produces an empty value.
+func (o *Int) UnmarshalGQL(v any) (err error) {
+	o.Optional, err = unmarshal(v, graphql.UnmarshalInt)
+	return err
+}
+
+// Float is an Optional[float64] bound to a GraphQL Float.
+type Float struct {
+	optional.Optional[float64]
+}
+
+// MarshalGQL implements graphql.Marshaler, writing null when empty.
+func (o Float) MarshalGQL(w io.Writer) {
+	marshal(w, o.Optional, graphql.MarshalFloat)
+}
+
+// UnmarshalGQL implements graphql.Unmarshaler. A nil input produces an empty value.
+func (o *Float) UnmarshalGQL(v any) (err error) {
+	o.Optional, err = unmarshal(v, graphql.UnmarshalFloat)
+	return err
+}
+
+// Boolean is an Optional[bool] bound to a GraphQL Boolean.
+type Boolean struct {
+	optional.Optional[bool]
+}
+
+// MarshalGQL implements graphql.Marshaler, writing null when empty.
+func (o Boolean) MarshalGQL(w io.Writer) {
+	marshal(w, o.Optional, graphql.MarshalBoolean)
+}
+
+// UnmarshalGQL implements graphql.Unmarshaler. A nil input produces an empty value.
+func (o *Boolean) UnmarshalGQL(v any) (err error) {
+	o.Optional, err = unmarshal(v, graphql.UnmarshalBoolean)
+	return err
+}
+
+// Time is an Optional[time.Time] bound to an RFC 3339 Time scalar.
+type Time struct {
+	optional.Optional[time.Time]
+}
+
+// MarshalGQL implements graphql.Marshaler, writing null when empty.
+func (o Time) MarshalGQL(w io.Writer) {
+	marshal(w, o.Optional, graphql.MarshalTime)
+}
+
+// UnmarshalGQL implements graphql.Unmarshaler. A nil input produces an empty value.
+func (o *Time) UnmarshalGQL(v any) (err error) {
+	o.Optional, err = unmarshal(v, graphql.UnmarshalTime)
+	return err
+}
+
+// Any is an Optional[any] bound to an Any scalar, passing the value through unchanged.
+type Any struct {
+	optional.Optional[any]
+}
+
+// MarshalGQL implements graphql.Marshaler, writing null when empty.
+func (o Any) MarshalGQL(w io.Writer) {
+	marshal(w, o.Optional, graphql.MarshalAny)
+}
+
+// UnmarshalGQL implements graphql.Unmarshaler. A nil input produces an empty value.
+func (o *Any) UnmarshalGQL(v any) (err error) {
+	o.Optional, err = unmarshal(v, graphql.UnmarshalAny)
+	return err
+}
+
+func marshal[T any](w io.Writer, o optional.Optional[T], marshalVal func(T) graphql.Marshaler) {
+	if val, ok := o.Get(); ok {
+		marshalVal(val).MarshalGQL(w)
+		return
+	}
+	graphql.Null.MarshalGQL(w)
+}
+
+func unmarshal[T any](v any, unmarshalVal func(any) (T, error)) (optional.Optional[T], error) {
+	if v == nil {
+		return optional.Empty[T](), nil
+	}
+	val, err := unmarshalVal(v)
+	if err != nil {
+		return optional.Empty[T](), err
+	}
+	return optional.Of(val), nil
+}
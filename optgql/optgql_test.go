@@ -0,0 +1,127 @@
+package optgql
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+var (
+	_ graphql.Marshaler   = String{}
+	_ graphql.Unmarshaler = (*String)(nil)
+	_ graphql.Marshaler   = Int{}
+	_ graphql.Unmarshaler = (*Int)(nil)
+	_ graphql.Marshaler   = Float{}
+	_ graphql.Unmarshaler = (*Float)(nil)
+	_ graphql.Marshaler   = Boolean{}
+	_ graphql.Unmarshaler = (*Boolean)(nil)
+	_ graphql.Marshaler   = Time{}
+	_ graphql.Unmarshaler = (*Time)(nil)
+	_ graphql.Marshaler   = Any{}
+	_ graphql.Unmarshaler = (*Any)(nil)
+)
+
+// wire marshals m and decodes the output the way gqlgen decodes request
+// variables, returning the value an Unmarshaler would receive.
+func wire(t *testing.T, m graphql.Marshaler) (string, any) {
+	t.Helper()
+	var buf bytes.Buffer
+	m.MarshalGQL(&buf)
+	dec := json.NewDecoder(bytes.NewReader(buf.Bytes()))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("MarshalGQL wrote invalid JSON %q: %v", buf.String(), err)
+	}
+	return buf.String(), v
+}
+
+func TestRoundTrip(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		in   graphql.Marshaler
+		out  interface {
+			graphql.Unmarshaler
+			graphql.Marshaler
+		}
+		wire string
+	}{
+		{"String", String{optional.Of("neo")}, &String{}, `"neo"`},
+		{"String zero", String{optional.Of("")}, &String{}, `""`},
+		{"String empty", String{}, &String{}, `null`},
+		{"Int", Int{optional.Of(-42)}, &Int{}, `-42`},
+		{"Int zero", Int{optional.Of(0)}, &Int{}, `0`},
+		{"Int empty", Int{}, &Int{}, `null`},
+		{"Float", Float{optional.Of(1.5)}, &Float{}, `1.5`},
+		{"Float empty", Float{}, &Float{}, `null`},
+		{"Boolean", Boolean{optional.Of(false)}, &Boolean{}, `false`},
+		{"Boolean empty", Boolean{}, &Boolean{}, `null`},
+		{"Time", Time{optional.Of(ts)}, &Time{}, `"2024-01-02T03:04:05Z"`},
+		{"Time empty", Time{}, &Time{}, `null`},
+		{"Any", Any{optional.Of[any](map[string]any{"a": "b"})}, &Any{}, `{"a":"b"}`},
+		{"Any empty", Any{}, &Any{}, `null`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			written, decoded := wire(t, tt.in)
+			if strings.TrimSpace(written) != tt.wire {
+				t.Errorf("MarshalGQL wrote %s, want %s", written, tt.wire)
+			}
+			if err := tt.out.UnmarshalGQL(decoded); err != nil {
+				t.Fatalf("UnmarshalGQL(%#v) = %v", decoded, err)
+			}
+			if got := reflect.ValueOf(tt.out).Elem().Interface(); !reflect.DeepEqual(got, tt.in) {
+				t.Errorf("round trip = %v, want %v", got, tt.in)
+			}
+		})
+	}
+}
+
+// Explicit null and an omitted input both produce an empty Optional, even
+// when unmarshaling into a value that was previously present.
+func TestNullInput(t *testing.T) {
+	s := String{optional.Of("stale")}
+	if err := s.UnmarshalGQL(nil); err != nil {
+		t.Fatal(err)
+	}
+	if s.IsPresent() {
+		t.Errorf("UnmarshalGQL(nil) = %v, want empty", s.Optional)
+	}
+
+	var omitted struct {
+		Nickname String
+	}
+	if omitted.Nickname.IsPresent() {
+		t.Error("omitted field is present")
+	}
+	var buf bytes.Buffer
+	omitted.Nickname.MarshalGQL(&buf)
+	if buf.String() != "null" {
+		t.Errorf("omitted field marshals as %s, want null", buf.String())
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	var i Int
+	if err := i.UnmarshalGQL("forty-two"); err == nil {
+		t.Error("Int.UnmarshalGQL(string) = nil, want an error")
+	}
+	if i.IsPresent() {
+		t.Errorf("failed UnmarshalGQL left %v, want empty", i.Optional)
+	}
+	var b Boolean
+	if err := b.UnmarshalGQL([]any{true}); err == nil {
+		t.Error("Boolean.UnmarshalGQL(list) = nil, want an error")
+	}
+	var tm Time
+	if err := tm.UnmarshalGQL("yesterday"); err == nil {
+		t.Error("Time.UnmarshalGQL(bad) = nil, want an error")
+	}
+}
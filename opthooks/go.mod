@@ -0,0 +1,10 @@
+module github.com/sergei-bronnikov/go-optional/opthooks
+
+go 1.18
+
+require (
+	github.com/go-viper/mapstructure/v2 v2.5.0
+	github.com/sergei-bronnikov/go-optional v0.0.0
+)
+
+replace github.com/sergei-bronnikov/go-optional => ../
@@ -0,0 +1,102 @@
+// Package opthooks provides mapstructure decode hooks for Optional fields, so
+// configuration loaded with mapstructure or viper can use Optionals. A key that
+// is missing from the input leaves its Optional field empty; a key that is
+// present is decoded into the element type and wrapped with optional.Of.
+//
+// Example usage with viper:
+//
+//	type Config struct {
+//	    Port    optional.Optional[int]           `mapstructure:"port"`
+//	    Timeout optional.Optional[time.Duration] `mapstructure:"timeout"`
+//	}
+//
+//	var cfg Config
+//	err := viper.Unmarshal(&cfg, viper.DecodeHook(opthooks.OptionalDecodeHook(
+//	    mapstructure.StringToTimeDurationHookFunc(),
+//	    mapstructure.StringToSliceHookFunc(","),
+//	)))
+package opthooks
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// OptionalDecodeHook returns a decode hook that runs the given hooks and then
+// decodes any value targeting an Optional field into the field's element type,
+// wrapping the result with optional.Of.
+//
+// The element is decoded by a nested decoder that uses the same hooks, so
+// standard hooks such as mapstructure.StringToTimeDurationHookFunc and
+// mapstructure.StringToTimeHookFunc apply to Optional[time.Duration] and
+// Optional[time.Time] fields as well as to plain ones. The nested decoder uses
+// weakly typed input, matching viper's defaults, so "8080" decodes into an
+// Optional[int].
+//
+// Example:
+//
+//	hook := opthooks.OptionalDecodeHook(mapstructure.StringToTimeDurationHookFunc())
+//	decoder, _ := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+//	    DecodeHook: hook,
+//	    Result:     &cfg,
+//	})
+func OptionalDecodeHook(hooks ...mapstructure.DecodeHookFunc) mapstructure.DecodeHookFunc {
+	var composed mapstructure.DecodeHookFunc
+	optionalHook := func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		elem, ok := optional.ElemType(to)
+		if !ok || from == to {
+			return data, nil
+		}
+		if data == nil {
+			return reflect.Zero(to).Interface(), nil
+		}
+		result := reflect.New(elem)
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       composed,
+			WeaklyTypedInput: true,
+			Result:           result.Interface(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := decoder.Decode(data); err != nil {
+			return nil, err
+		}
+		return optional.ReflectOf(to, result.Elem()).Interface(), nil
+	}
+	all := make([]mapstructure.DecodeHookFunc, 0, len(hooks)+1)
+	all = append(all, hooks...)
+	all = append(all, mapstructure.DecodeHookFuncType(optionalHook))
+	composed = mapstructure.ComposeDecodeHookFunc(all...)
+	return composed
+}
+
+// StringToOptionalHookFunc returns a decode hook that treats a blank string
+// targeting an Optional field as absent, leaving the field empty. This is
+// useful for environment variables, which are often set to "" rather than
+// unset. Non-blank strings are passed through unchanged, so the hook must be
+// composed before OptionalDecodeHook.
+//
+// Example:
+//
+//	hook := mapstructure.ComposeDecodeHookFunc(
+//	    opthooks.StringToOptionalHookFunc(),
+//	    opthooks.OptionalDecodeHook(),
+//	)
+func StringToOptionalHookFunc() mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		if from.Kind() != reflect.String {
+			return data, nil
+		}
+		if _, ok := optional.ElemType(to); !ok {
+			return data, nil
+		}
+		if strings.TrimSpace(reflect.ValueOf(data).String()) == "" {
+			return reflect.Zero(to).Interface(), nil
+		}
+		return data, nil
+	}
+}
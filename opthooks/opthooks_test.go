@@ -0,0 +1,186 @@
+package opthooks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+type Limits struct {
+	Rate  int  `mapstructure:"rate"`
+	Burst *int `mapstructure:"burst"`
+}
+
+type config struct {
+	Port     optional.Optional[int]           `mapstructure:"port"`
+	Timeout  optional.Optional[time.Duration] `mapstructure:"timeout"`
+	Since    optional.Optional[time.Time]     `mapstructure:"since"`
+	Tags     optional.Optional[[]string]      `mapstructure:"tags"`
+	Limits   optional.Optional[Limits]        `mapstructure:"limits"`
+	Name     optional.Optional[string]        `mapstructure:"name"`
+	Debug    optional.Optional[bool]          `mapstructure:"debug"`
+	Interval time.Duration                    `mapstructure:"interval"`
+}
+
+func decode(t *testing.T, hook mapstructure.DecodeHookFunc, input map[string]any) (config, error) {
+	t.Helper()
+	var cfg config
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: hook,
+		Result:     &cfg,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg, decoder.Decode(input)
+}
+
+func TestOptionalDecodeHook(t *testing.T) {
+	hook := OptionalDecodeHook(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToTimeHookFunc(time.RFC3339),
+		mapstructure.StringToSliceHookFunc(","),
+	)
+	cfg, err := decode(t, hook, map[string]any{
+		"port":     8080,
+		"timeout":  "1m30s",
+		"since":    "2024-01-02T15:04:05Z",
+		"tags":     "a,b",
+		"limits":   map[string]any{"rate": 10},
+		"name":     "",
+		"interval": "5s",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := config{
+		Port:     optional.Of(8080),
+		Timeout:  optional.Of(90 * time.Second),
+		Since:    optional.Of(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)),
+		Tags:     optional.Of([]string{"a", "b"}),
+		Limits:   optional.Of(Limits{Rate: 10}),
+		Name:     optional.Of(""),
+		Interval: 5 * time.Second,
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Decode =\n%+v\nwant\n%+v", cfg, want)
+	}
+}
+
+func TestOptionalDecodeHookWithoutHooks(t *testing.T) {
+	_, err := decode(t, OptionalDecodeHook(), map[string]any{"timeout": "1m30s"})
+	if err == nil {
+		t.Error("decoding a duration string without StringToTimeDurationHookFunc succeeded")
+	}
+}
+
+func TestOptionalDecodeHookWeaklyTyped(t *testing.T) {
+	// The outer decoder is strict; the nested one decoding the Optional's
+	// element is weakly typed.
+	burst := 5
+	cfg, err := decode(t, OptionalDecodeHook(), map[string]any{
+		"port":   "8080",
+		"debug":  "1",
+		"name":   42,
+		"limits": map[string]any{"rate": "10", "burst": "5"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := config{
+		Port:   optional.Of(8080),
+		Debug:  optional.Of(true),
+		Name:   optional.Of("42"),
+		Limits: optional.Of(Limits{Rate: 10, Burst: &burst}),
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Decode =\n%+v\nwant\n%+v", cfg, want)
+	}
+
+	if _, err := decode(t, OptionalDecodeHook(), map[string]any{"port": "eighty"}); err == nil || !strings.Contains(err.Error(), "'port'") {
+		t.Errorf("decoding a malformed nested value error = %v", err)
+	}
+}
+
+func TestOptionalDecodeHookNil(t *testing.T) {
+	cfg, err := decode(t, OptionalDecodeHook(), map[string]any{"port": nil, "limits": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port.IsPresent() || cfg.Limits.IsPresent() {
+		t.Errorf("nil data decoded as present: %+v", cfg)
+	}
+
+	cfg, err = decode(t, OptionalDecodeHook(), map[string]any{})
+	if err != nil || !reflect.DeepEqual(cfg, config{}) {
+		t.Errorf("missing keys = %+v, %v, want every field empty", cfg, err)
+	}
+}
+
+func TestOptionalDecodeHookPassesOptionals(t *testing.T) {
+	cfg, err := decode(t, OptionalDecodeHook(), map[string]any{"port": optional.Of(1), "name": optional.Empty[string]()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != optional.Of(1) || cfg.Name.IsPresent() {
+		t.Errorf("Optional input decoded as %+v", cfg)
+	}
+}
+
+func TestStringToOptionalHookFunc(t *testing.T) {
+	hook := mapstructure.ComposeDecodeHookFunc(
+		StringToOptionalHookFunc(),
+		OptionalDecodeHook(mapstructure.StringToTimeDurationHookFunc()),
+	)
+	cfg, err := decode(t, hook, map[string]any{"port": "  ", "timeout": "", "name": "x", "debug": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := config{Name: optional.Of("x"), Debug: optional.Of(true)}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Decode = %+v, want %+v", cfg, want)
+	}
+}
+
+// TestViperExample runs the package example through a decoder configured
+// as viper.Unmarshal configures it: weakly typed input, with the hook
+// given by viper.DecodeHook.
+func TestViperExample(t *testing.T) {
+	type Config struct {
+		Port    optional.Optional[int]           `mapstructure:"port"`
+		Timeout optional.Optional[time.Duration] `mapstructure:"timeout"`
+		Hosts   optional.Optional[[]string]      `mapstructure:"hosts"`
+		Retries optional.Optional[int]           `mapstructure:"retries"`
+	}
+	// Settings as viper holds them after reading PORT=8080 and
+	// TIMEOUT=30s from the environment and hosts from a config file.
+	settings := map[string]any{"port": "8080", "timeout": "30s", "hosts": "a.local,b.local"}
+
+	var cfg Config
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: OptionalDecodeHook(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+		WeaklyTypedInput: true,
+		Result:           &cfg,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decoder.Decode(settings); err != nil {
+		t.Fatal(err)
+	}
+	want := Config{
+		Port:    optional.Of(8080),
+		Timeout: optional.Of(30 * time.Second),
+		Hosts:   optional.Of([]string{"a.local", "b.local"}),
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Decode = %+v, want %+v", cfg, want)
+	}
+}
@@ -15,13 +15,29 @@ package optional
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 )
 
 // Optional is a container object which may or may not contain a value.
 // If a value is present, IsPresent() returns true. If no value is present,
 // IsEmpty() returns true.
+//
+// The zero value of Optional is an empty Optional and behaves exactly like
+// Empty[T]() in every method. The presence checks IsPresent, IsEmpty,
+// IsPresentAnd and IsEmptyOr have pointer receivers and treat a nil *Optional
+// as empty, so they are safe on a nil field of a lazily initialized struct;
+// like any pointer method, they need an addressable Optional, such as a
+// variable, rather than the direct result of a function call. The other
+// read-only methods use value receivers, so Optional satisfies fmt.Stringer
+// whether it is held by value or by pointer, but calling them through a nil
+// *Optional panics.
+//
+// The value is stored inline rather than behind a pointer, so constructing an
+// Optional never allocates. The trade-off is that an Optional is as large as T
+// plus a presence flag, and copying it copies the contained value.
 type Optional[T any] struct {
-	value *T
+	value   T
+	present bool
 }
 
 // Of returns an Optional containing the given value.
@@ -30,11 +46,12 @@ type Optional[T any] struct {
 //
 //	opt := optional.Of("hello")
 func Of[T any](val T) Optional[T] {
-	return Optional[T]{value: &val}
+	return Optional[T]{value: val, present: true}
 }
 
 // OfNullable returns an Optional containing the value pointed to by val if val is not nil,
-// otherwise returns an empty Optional.
+// otherwise returns an empty Optional. The pointee is copied, so later changes made
+// through val are not visible in the returned Optional.
 //
 // Example:
 //
@@ -45,9 +62,95 @@ func Of[T any](val T) Optional[T] {
 //	opt = optional.OfNullable(&str) // Optional with "hello"
 func OfNullable[T any](val *T) Optional[T] {
 	if val == nil {
-		return Optional[T]{value: nil}
+		return Optional[T]{}
+	}
+	return Optional[T]{value: *val, present: true}
+}
+
+// OfNotNil returns an Optional containing val, or an empty Optional if val is
+// nil. Unlike Of, it uses reflection to also detect nil pointers, maps, slices,
+// functions and channels, including typed nils stored in interface values such
+// as an error holding a nil *MyErr.
+//
+// Of cannot do this itself: a nil slice or map is a legitimate present value
+// for non-interface T, and inspecting every value through reflection would
+// make the allocation-free Of allocate. Use OfNotNil at boundaries where a nil
+// means "absent", typically with interface types such as error or any.
+//
+// Example:
+//
+//	var err error = (*MyErr)(nil)
+//	opt := optional.OfNotNil(err) // empty Optional
+func OfNotNil[T any](val T) Optional[T] {
+	if isNil(val) {
+		return Empty[T]()
+	}
+	return Of(val)
+}
+
+// Deref collapses an Optional of a pointer into an Optional of the pointee. The
+// result is empty if o is empty or holds a nil pointer, and otherwise holds a
+// copy of the pointee, so a present result can never cause a nil dereference.
+//
+// Example:
+//
+//	var p *User
+//	opt := optional.Deref(optional.Of(p)) // empty Optional
+func Deref[T any](o Optional[*T]) Optional[T] {
+	if !o.present {
+		return Empty[T]()
+	}
+	return OfNullable(o.value)
+}
+
+// Ref converts an Optional of a value into an Optional of a pointer to a copy of
+// that value. It is the inverse of Deref: the result is empty if o is empty.
+//
+// Example:
+//
+//	opt := optional.Ref(optional.Of(42)) // Optional of a *int pointing to 42
+func Ref[T any](o Optional[T]) Optional[*T] {
+	if !o.present {
+		return Empty[*T]()
+	}
+	val := o.value
+	return Of(&val)
+}
+
+// As converts an Optional[any] into an Optional[T] by type-asserting the
+// contained value. The result is present if o is present and its value
+// asserts to T, which may be a concrete or an interface type, and empty
+// otherwise. As with a plain type assertion, a present nil value does not
+// assert to any type.
+//
+// Example:
+//
+//	var raw optional.Optional[any] = optional.Of[any](42)
+//	n := optional.As[int](raw)          // Optional[42]
+//	s := optional.As[fmt.Stringer](raw) // empty Optional
+func As[T any](o Optional[any]) Optional[T] {
+	result, _ := AsOk[T](o)
+	return result
+}
+
+// AsOk is like As, but also reports whether the assertion succeeded. ok is
+// false only when o is present and its value does not assert to T; an empty
+// o yields an empty result with ok set to true.
+//
+// Example:
+//
+//	n, ok := optional.AsOk[int](raw)
+//	if !ok {
+//	    return fmt.Errorf("expected an int, got %T", raw.OrNil())
+//	}
+func AsOk[T any](o Optional[any]) (result Optional[T], ok bool) {
+	if !o.present {
+		return Empty[T](), true
+	}
+	if val, ok := o.value.(T); ok {
+		return Of(val), true
 	}
-	return Optional[T]{value: val}
+	return Empty[T](), false
 }
 
 // Empty returns an empty Optional instance.
@@ -56,10 +159,57 @@ func OfNullable[T any](val *T) Optional[T] {
 //
 //	opt := optional.Empty[string]()
 func Empty[T any]() Optional[T] {
-	return Optional[T]{value: nil}
+	return Optional[T]{}
+}
+
+// When returns an Optional containing v if cond is true, otherwise an empty Optional.
+//
+// Example:
+//
+//	limit := optional.When(includeLimit, 100)
+func When[T any](cond bool, v T) Optional[T] {
+	if cond {
+		return Of(v)
+	}
+	return Empty[T]()
+}
+
+// WhenFunc returns an Optional containing the result of fn if cond is true,
+// otherwise an empty Optional. fn is only called when cond is true, so it may
+// rely on whatever the condition guards.
+//
+// Example:
+//
+//	name := optional.WhenFunc(user != nil, func() string { return user.Name })
+func WhenFunc[T any](cond bool, fn func() T) Optional[T] {
+	if cond {
+		return Of(fn())
+	}
+	return Empty[T]()
+}
+
+// Unless returns an Optional containing v if cond is false, otherwise an empty
+// Optional. It is the negation of When.
+//
+// Example:
+//
+//	offset := optional.Unless(firstPage, page*size)
+func Unless[T any](cond bool, v T) Optional[T] {
+	return When(!cond, v)
+}
+
+// UnlessFunc returns an Optional containing the result of fn if cond is false,
+// otherwise an empty Optional. fn is only called when cond is false.
+//
+// Example:
+//
+//	cursor := optional.UnlessFunc(done, func() string { return it.Cursor() })
+func UnlessFunc[T any](cond bool, fn func() T) Optional[T] {
+	return WhenFunc(!cond, fn)
 }
 
-// IsPresent returns true if a value is present, otherwise false.
+// IsPresent returns true if a value is present, otherwise false. It may be
+// called on a nil *Optional, which is empty.
 //
 // Example:
 //
@@ -68,10 +218,11 @@ func Empty[T any]() Optional[T] {
 //	    fmt.Println("Value exists")
 //	}
 func (o *Optional[T]) IsPresent() bool {
-	return o.value != nil
+	return o != nil && o.present
 }
 
-// IsEmpty returns true if no value is present, otherwise false.
+// IsEmpty returns true if no value is present, otherwise false. It may be
+// called on a nil *Optional, which is empty.
 //
 // Example:
 //
@@ -80,7 +231,31 @@ func (o *Optional[T]) IsPresent() bool {
 //	    fmt.Println("No value")
 //	}
 func (o *Optional[T]) IsEmpty() bool {
-	return o.value == nil
+	return o == nil || !o.present
+}
+
+// IsPresentAnd returns true if a value is present and pred returns true for
+// it. pred is not called if o is empty or a nil pointer.
+//
+// Example:
+//
+//	if req.Limit.IsPresentAnd(func(n int) bool { return n > maxLimit }) {
+//	    return errLimitTooLarge
+//	}
+func (o *Optional[T]) IsPresentAnd(pred func(T) bool) bool {
+	return o.IsPresent() && pred(o.value)
+}
+
+// IsEmptyOr returns true if no value is present, or if pred returns true for
+// the value. pred is not called if o is empty or a nil pointer.
+//
+// Example:
+//
+//	if !cfg.Timeout.IsEmptyOr(func(d time.Duration) bool { return d > 0 }) {
+//	    return errors.New("timeout must be positive")
+//	}
+func (o *Optional[T]) IsEmptyOr(pred func(T) bool) bool {
+	return o.IsEmpty() || pred(o.value)
 }
 
 // Get returns the value if present, along with a boolean indicating whether
@@ -94,9 +269,9 @@ func (o *Optional[T]) IsEmpty() bool {
 //	if ok {
 //	    fmt.Println(value)
 //	}
-func (o *Optional[T]) Get() (T, bool) {
-	if o.value != nil {
-		return *o.value, true
+func (o Optional[T]) Get() (T, bool) {
+	if o.present {
+		return o.value, true
 	}
 	var zero T
 	return zero, false
@@ -111,13 +286,78 @@ func (o *Optional[T]) Get() (T, bool) {
 //
 //	opt = optional.Of("hello")
 //	value = opt.OrElse("default") // returns "hello"
-func (o *Optional[T]) OrElse(other T) T {
-	if o.value != nil {
-		return *o.value
+func (o Optional[T]) OrElse(other T) T {
+	if o.present {
+		return o.value
 	}
 	return other
 }
 
+// Inspect calls fn with a copy of the value if present and returns the Optional
+// unchanged either way, so it can be inserted anywhere in a chain for logging or
+// debugging. fn is not called if the Optional is empty.
+//
+// Example:
+//
+//	opt := optional.Of(42).Inspect(func(v int) {
+//	    log.Printf("got %d", v)
+//	})
+func (o Optional[T]) Inspect(fn func(T)) Optional[T] {
+	if o.present {
+		fn(o.value)
+	}
+	return o
+}
+
+// Update calls fn with a pointer to the stored value if present, so a large
+// value can be modified in place instead of copied out with Get and back in
+// with Of. It does nothing if the Optional is empty or o is nil.
+//
+// The pointer aliases o's storage: it must not be retained after fn returns,
+// since copying o later copies the value and leaves the pointer behind. Update
+// is not safe for concurrent use with other access to o.
+//
+// Example:
+//
+//	doc.Update(func(d *Document) {
+//	    d.Title = strings.TrimSpace(d.Title)
+//	})
+func (o *Optional[T]) Update(fn func(*T)) {
+	if o.IsPresent() {
+		fn(&o.value)
+	}
+}
+
+// IsNil returns true if a value is present but is nil: a nil pointer, map,
+// slice, function or channel, or an interface holding nil or a typed nil. It
+// identifies Optionals that report present yet would still fail at use time.
+//
+// Example:
+//
+//	var err error = (*MyErr)(nil)
+//	opt := optional.Of(err)
+//	opt.IsNil() // true
+func (o Optional[T]) IsNil() bool {
+	return o.present && isNil(o.value)
+}
+
+// OrNil returns the value as an interface if present, otherwise nil. It is mainly
+// useful in text/template and html/template, where "{{ with .Field.OrNil }}"
+// renders its body only when the value is present.
+//
+// Example:
+//
+//	opt := optional.Of("hello")
+//	v := opt.OrNil() // "hello"
+//
+//	v = optional.Empty[string]().OrNil() // nil
+func (o Optional[T]) OrNil() any {
+	if o.present {
+		return o.value
+	}
+	return nil
+}
+
 // Equals compares this Optional with another Optional for equality.
 // Two Optionals are considered equal if both are empty, or if both contain
 // values that are deeply equal.
@@ -127,13 +367,28 @@ func (o *Optional[T]) OrElse(other T) T {
 //	opt1 := optional.Of(42)
 //	opt2 := optional.Of(42)
 //	opt1.Equals(opt2) // returns true
-func (o *Optional[T]) Equals(other Optional[T]) bool {
+func (o Optional[T]) Equals(other Optional[T]) bool {
 	if o.IsPresent() && other.IsPresent() {
-		return reflect.DeepEqual(*o.value, *other.value)
+		return reflect.DeepEqual(o.value, other.value)
 	}
 	return o.IsEmpty() && other.IsEmpty()
 }
 
+// EqualNaN is like Equals for floating-point Optionals, except that two NaN
+// values are equal, so NaN can serve as a marker value. Other values compare
+// as with ==: 0 equals -0, and each infinity equals only itself.
+//
+// Example:
+//
+//	optional.Of(math.NaN()).Equals(optional.Of(math.NaN())) // false
+//	optional.EqualNaN(optional.Of(math.NaN()), optional.Of(math.NaN())) // true
+func EqualNaN[T Float](a, b Optional[T]) bool {
+	if a.present && b.present {
+		return a.value == b.value || (a.value != a.value && b.value != b.value)
+	}
+	return a.IsEmpty() && b.IsEmpty()
+}
+
 // String returns a string representation of the Optional.
 // If a value is present, returns "Optional[value]".
 // If no value is present, returns "Optional.empty".
@@ -142,9 +397,112 @@ func (o *Optional[T]) Equals(other Optional[T]) bool {
 //
 //	opt := optional.Of("hello")
 //	fmt.Println(opt.String()) // Output: Optional[hello]
-func (o *Optional[T]) String() string {
-	if o.value != nil {
-		return fmt.Sprintf("Optional[%v]", *o.value)
+func (o Optional[T]) String() string {
+	if !o.present {
+		return "Optional.empty"
+	}
+	if s, ok := any(o.value).(string); ok {
+		return "Optional[" + s + "]"
+	}
+	var buf [64]byte
+	return string(o.AppendString(buf[:0]))
+}
+
+// AppendString appends the representation returned by String to dst and
+// returns the extended buffer. Strings, booleans, integers and floats are
+// formatted with strconv without intermediate allocations; other types, and
+// named types that may define their own String method, go through fmt.
+//
+// Example:
+//
+//	buf = append(buf, "user="...)
+//	buf = user.Nickname().AppendString(buf)
+func (o Optional[T]) AppendString(dst []byte) []byte {
+	if !o.present {
+		return append(dst, "Optional.empty"...)
+	}
+	dst = append(dst, "Optional["...)
+	switch v := any(o.value).(type) {
+	case string:
+		dst = append(dst, v...)
+	case bool:
+		dst = strconv.AppendBool(dst, v)
+	case int:
+		dst = strconv.AppendInt(dst, int64(v), 10)
+	case int8:
+		dst = strconv.AppendInt(dst, int64(v), 10)
+	case int16:
+		dst = strconv.AppendInt(dst, int64(v), 10)
+	case int32:
+		dst = strconv.AppendInt(dst, int64(v), 10)
+	case int64:
+		dst = strconv.AppendInt(dst, v, 10)
+	case uint:
+		dst = strconv.AppendUint(dst, uint64(v), 10)
+	case uint8:
+		dst = strconv.AppendUint(dst, uint64(v), 10)
+	case uint16:
+		dst = strconv.AppendUint(dst, uint64(v), 10)
+	case uint32:
+		dst = strconv.AppendUint(dst, uint64(v), 10)
+	case uint64:
+		dst = strconv.AppendUint(dst, v, 10)
+	case uintptr:
+		dst = strconv.AppendUint(dst, uint64(v), 10)
+	case float32:
+		dst = strconv.AppendFloat(dst, float64(v), 'g', -1, 32)
+	case float64:
+		dst = strconv.AppendFloat(dst, v, 'g', -1, 64)
+	default:
+		dst = append(dst, fmt.Sprintf("%v", o.value)...)
+	}
+	return append(dst, ']')
+}
+
+// StringWith returns a string representation of the Optional, applying the given
+// fmt format to the contained value. If no value is present, returns
+// "Optional.empty" regardless of the format.
+//
+// Example:
+//
+//	opt := optional.Of([]byte("hi"))
+//	fmt.Println(opt.StringWith("%x")) // Output: Optional[6869]
+func (o Optional[T]) StringWith(format string) string {
+	if o.present {
+		return "Optional[" + fmt.Sprintf(format, o.value) + "]"
 	}
 	return "Optional.empty"
 }
+
+// Formatter renders Optionals using a custom function for the contained value.
+//
+// Example:
+//
+//	date := optional.Formatter[time.Time](func(t time.Time) string {
+//	    return t.Format(time.DateOnly)
+//	})
+//	fmt.Println(date.Format(optional.Of(t))) // Output: Optional[2024-01-02]
+type Formatter[T any] func(T) string
+
+// Format returns "Optional[f(value)]" if a value is present, otherwise
+// "Optional.empty". f is only called when a value is present.
+func (f Formatter[T]) Format(o Optional[T]) string {
+	if o.present {
+		return "Optional[" + f(o.value) + "]"
+	}
+	return "Optional.empty"
+}
+
+// isNil reports whether val is nil or a nil pointer, map, slice, function,
+// channel or unsafe pointer, looking through interfaces.
+func isNil(val any) bool {
+	if val == nil {
+		return true
+	}
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+		return rv.IsNil()
+	}
+	return false
+}
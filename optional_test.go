@@ -0,0 +1,575 @@
+package optional
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"testing"
+)
+
+// kilobyte is a 1 KB value, large enough that copying it shows up next to the
+// cost of the Optional operations themselves.
+type kilobyte struct {
+	data [1024]byte
+}
+
+var (
+	sinkInt      int
+	sinkString   string
+	sinkKilobyte kilobyte
+	sinkBool     bool
+
+	sinkOptInt      Optional[int]
+	sinkOptString   Optional[string]
+	sinkOptKilobyte Optional[kilobyte]
+)
+
+func BenchmarkOf(b *testing.B) {
+	b.Run("int", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkOptInt = Of(i)
+		}
+	})
+	b.Run("string", func(b *testing.B) {
+		s := "hello"
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkOptString = Of(s)
+		}
+	})
+	b.Run("1KB", func(b *testing.B) {
+		var v kilobyte
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkOptKilobyte = Of(v)
+		}
+	})
+}
+
+func BenchmarkGet(b *testing.B) {
+	b.Run("int", func(b *testing.B) {
+		o := Of(42)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkInt, sinkBool = o.Get()
+		}
+	})
+	b.Run("string", func(b *testing.B) {
+		o := Of("hello")
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkString, sinkBool = o.Get()
+		}
+	})
+	b.Run("1KB", func(b *testing.B) {
+		o := Of(kilobyte{})
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkKilobyte, sinkBool = o.Get()
+		}
+	})
+}
+
+func BenchmarkOrElse(b *testing.B) {
+	b.Run("int", func(b *testing.B) {
+		o := Empty[int]()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkInt = o.OrElse(i)
+		}
+	})
+	b.Run("string", func(b *testing.B) {
+		o := Empty[string]()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkString = o.OrElse("fallback")
+		}
+	})
+	b.Run("1KB", func(b *testing.B) {
+		o := Empty[kilobyte]()
+		var def kilobyte
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkKilobyte = o.OrElse(def)
+		}
+	})
+}
+
+func TestOfDoesNotAllocate(t *testing.T) {
+	var v kilobyte
+	tests := map[string]func(){
+		"int":    func() { sinkOptInt = Of(42) },
+		"string": func() { sinkOptString = Of("hello") },
+		"1KB":    func() { sinkOptKilobyte = Of(v) },
+	}
+	for name, fn := range tests {
+		if allocs := testing.AllocsPerRun(100, fn); allocs != 0 {
+			t.Errorf("Of(%s) allocates %v times, want 0", name, allocs)
+		}
+	}
+}
+
+type celsius float64
+
+func (c celsius) String() string { return "warm" }
+
+func TestAs(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     Optional[any]
+		as     func(Optional[any]) (plain, result Optional[any], ok bool)
+		want   Optional[any]
+		wantOk bool
+	}{
+		{"concrete", Of[any](42), asOk[int], Of[any](42), true},
+		{"interface", Of[any](celsius(21)), asOk[fmt.Stringer], Of[any](celsius(21)), true},
+		{"error interface", Of[any](io.EOF), asOk[error], Of[any](io.EOF), true},
+		{"wrong concrete type", Of[any]("42"), asOk[int], Empty[any](), false},
+		{"missing method", Of[any](42), asOk[fmt.Stringer], Empty[any](), false},
+		{"named vs underlying", Of[any](celsius(21)), asOk[float64], Empty[any](), false},
+		{"present nil", Of[any](nil), asOk[error], Empty[any](), false},
+		{"empty", Empty[any](), asOk[int], Empty[any](), true},
+		{"empty interface target", Empty[any](), asOk[fmt.Stringer], Empty[any](), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain, got, ok := tt.as(tt.in)
+			if !got.Equals(tt.want) || ok != tt.wantOk {
+				t.Errorf("AsOk(%v) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.wantOk)
+			}
+			if !plain.Equals(tt.want) {
+				t.Errorf("As(%v) = %v, want %v", tt.in, plain, tt.want)
+			}
+		})
+	}
+}
+
+// asOk runs As and AsOk for T and widens their results back to
+// Optional[any], so cases with different targets fit in one table.
+func asOk[T any](o Optional[any]) (plain, result Optional[any], ok bool) {
+	r, ok := AsOk[T](o)
+	return widen(As[T](o)), widen(r), ok
+}
+
+func widen[T any](o Optional[T]) Optional[any] {
+	if v, ok := o.Get(); ok {
+		return Of[any](v)
+	}
+	return Empty[any]()
+}
+
+type nilErr struct{}
+
+func (*nilErr) Error() string { return "nilErr" }
+
+func TestOfNotNil(t *testing.T) {
+	var (
+		nilPtr   *int
+		nilMap   map[string]int
+		nilSlice []int
+		nilFunc  func()
+		nilChan  chan int
+		nilError *nilErr
+		n        = 1
+	)
+	tests := []struct {
+		name string
+		// check reports whether OfNotNil(v) is present and whether Of(v).IsNil().
+		check func() (present, isNil bool)
+		want  bool // the value is non-nil
+	}{
+		{"nil pointer", nilCheck(nilPtr), false},
+		{"nil map", nilCheck(nilMap), false},
+		{"nil slice", nilCheck(nilSlice), false},
+		{"nil func", nilCheck(nilFunc), false},
+		{"nil chan", nilCheck(nilChan), false},
+		{"untyped nil interface", nilCheck[error](nil), false},
+		{"typed nil in error", nilCheck[error](nilError), false},
+		{"typed nil in any", nilCheck[any](nilPtr), false},
+		{"typed nil map in any", nilCheck[any](nilMap), false},
+		{"typed nil slice in any", nilCheck[any](nilSlice), false},
+		{"typed nil func in any", nilCheck[any](nilFunc), false},
+		{"typed nil chan in any", nilCheck[any](nilChan), false},
+		{"pointer", nilCheck(&n), true},
+		{"empty map", nilCheck(map[string]int{}), true},
+		{"empty slice", nilCheck([]int{}), true},
+		{"func", nilCheck(func() {}), true},
+		{"chan", nilCheck(make(chan int)), true},
+		{"error", nilCheck[error](io.EOF), true},
+		{"zero int", nilCheck(0), true},
+		{"zero int in any", nilCheck[any](0), true},
+		{"empty string", nilCheck(""), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			present, isNil := tt.check()
+			if present != tt.want {
+				t.Errorf("OfNotNil(...).IsPresent() = %v, want %v", present, tt.want)
+			}
+			if isNil == tt.want {
+				t.Errorf("Of(...).IsNil() = %v, want %v", isNil, !tt.want)
+			}
+		})
+	}
+
+	if Empty[*int]().IsNil() || Empty[any]().IsNil() {
+		t.Error("Empty().IsNil() = true, want false")
+	}
+}
+
+func nilCheck[T any](v T) func() (present, isNil bool) {
+	return func() (bool, bool) {
+		o := OfNotNil(v)
+		return o.IsPresent(), Of(v).IsNil()
+	}
+}
+
+type stringerID int
+
+func (id stringerID) String() string { return "id-" + strconv.Itoa(int(id)) }
+
+type namedFloat float64
+
+// formatCase formats v the way String used to, with fmt, next to the output
+// of String and AppendString.
+type formatCase struct {
+	want, got, appended string
+}
+
+func format[T any](v T) formatCase {
+	o := Of(v)
+	return formatCase{
+		want:     fmt.Sprintf("Optional[%v]", v),
+		got:      o.String(),
+		appended: string(o.AppendString([]byte("x="))),
+	}
+}
+
+// String must keep the format of fmt.Sprintf("Optional[%v]", v) for every
+// kind, fast path or not.
+func TestStringFormat(t *testing.T) {
+	tests := []formatCase{
+		format("hello"), format(""), format("ünï\ncode"),
+		format(true), format(false),
+		format(0), format(-42), format(int8(math.MinInt8)), format(int16(math.MaxInt16)),
+		format(int32(math.MinInt32)), format(int64(math.MinInt64)),
+		format(uint(7)), format(uint8(255)), format(uint16(math.MaxUint16)),
+		format(uint32(math.MaxUint32)), format(uint64(math.MaxUint64)), format(uintptr(0xff)),
+		format(1.5), format(1e21), format(1e20), format(1e-7), format(123456789.0),
+		format(math.Copysign(0, -1)), format(math.NaN()), format(math.Inf(1)), format(math.Inf(-1)),
+		format(float32(0.1)), format(float32(1e21)), format(float32(math.MaxFloat32)),
+		format(stringerID(5)), format(namedFloat(2.5)), format('x'), format(complex(1, -2)),
+		format([]int{1, 2}), format(map[string]int{"a": 1}), format(struct{ A int }{1}),
+		format((*int)(nil)), format[any](nil), format(errors.New("boom")),
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("String() = %q, want %q", tt.got, tt.want)
+		}
+		if tt.appended != "x="+tt.want {
+			t.Errorf("AppendString() = %q, want %q", tt.appended, "x="+tt.want)
+		}
+	}
+	if got := Empty[int]().String(); got != "Optional.empty" {
+		t.Errorf("empty String() = %q", got)
+	}
+}
+
+func TestAppendStringDoesNotAllocate(t *testing.T) {
+	buf := make([]byte, 0, 64)
+	tests := map[string]func(){
+		"string":  func() { buf = Of("hello").AppendString(buf[:0]) },
+		"int64":   func() { buf = Of(int64(-1234567)).AppendString(buf[:0]) },
+		"uint8":   func() { buf = Of(uint8(200)).AppendString(buf[:0]) },
+		"float64": func() { buf = Of(3.25).AppendString(buf[:0]) },
+		"bool":    func() { buf = Of(true).AppendString(buf[:0]) },
+		"empty":   func() { buf = Empty[string]().AppendString(buf[:0]) },
+	}
+	for name, fn := range tests {
+		if allocs := testing.AllocsPerRun(100, fn); allocs != 0 {
+			t.Errorf("AppendString(%s) allocates %v times, want 0", name, allocs)
+		}
+	}
+	// String allocates only the returned string.
+	for name, fn := range map[string]func(){
+		"string": func() { sinkString = Of("hello").String() },
+		"int64":  func() { sinkString = Of(int64(-1234567)).String() },
+	} {
+		if allocs := testing.AllocsPerRun(100, fn); allocs > 1 {
+			t.Errorf("String(%s) allocates %v times, want at most 1", name, allocs)
+		}
+	}
+}
+
+// BenchmarkString compares String and AppendString with the fmt-based
+// formatting String used before.
+func BenchmarkString(b *testing.B) {
+	str, i64 := Of("user-42"), Of(int64(1234567890))
+	buf := make([]byte, 0, 64)
+	b.Run("string/fmt", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkString = fmt.Sprintf("Optional[%v]", str.value)
+		}
+	})
+	b.Run("string/String", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkString = str.String()
+		}
+	})
+	b.Run("string/AppendString", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf = str.AppendString(buf[:0])
+		}
+	})
+	b.Run("int64/fmt", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkString = fmt.Sprintf("Optional[%v]", i64.value)
+		}
+	})
+	b.Run("int64/String", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sinkString = i64.String()
+		}
+	})
+	b.Run("int64/AppendString", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf = i64.AppendString(buf[:0])
+		}
+	})
+}
+
+func TestEqualNaN(t *testing.T) {
+	nan, inf, negZero := math.NaN(), math.Inf(1), math.Copysign(0, -1)
+	tests := []struct {
+		name string
+		a, b Optional[float64]
+		want bool
+		// equals is the result of Equals, which differs only for NaN.
+		equals bool
+	}{
+		{"NaN vs NaN", Of(nan), Of(nan), true, false},
+		{"NaN vs other NaN payload", Of(nan), Of(math.Float64frombits(0x7ff8000000000001)), true, false},
+		{"NaN vs number", Of(nan), Of(1.0), false, false},
+		{"number vs NaN", Of(0.0), Of(nan), false, false},
+		{"NaN vs +Inf", Of(nan), Of(inf), false, false},
+		{"+0 vs -0", Of(0.0), Of(negZero), true, true},
+		{"-0 vs -0", Of(negZero), Of(negZero), true, true},
+		{"+Inf vs +Inf", Of(inf), Of(inf), true, true},
+		{"-Inf vs -Inf", Of(-inf), Of(-inf), true, true},
+		{"+Inf vs -Inf", Of(inf), Of(-inf), false, false},
+		{"+Inf vs MaxFloat64", Of(inf), Of(math.MaxFloat64), false, false},
+		{"equal numbers", Of(1.5), Of(1.5), true, true},
+		{"different numbers", Of(1.5), Of(2.5), false, false},
+		{"both empty", Empty[float64](), Optional[float64]{}, true, true},
+		{"empty vs NaN", Empty[float64](), Of(nan), false, false},
+		{"NaN vs empty", Of(nan), Empty[float64](), false, false},
+		{"empty vs zero", Empty[float64](), Of(0.0), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EqualNaN(tt.a, tt.b); got != tt.want {
+				t.Errorf("EqualNaN(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if got := EqualNaN(tt.b, tt.a); got != tt.want {
+				t.Errorf("EqualNaN(%v, %v) = %v, want %v", tt.b, tt.a, got, tt.want)
+			}
+			if got := tt.a.Equals(tt.b); got != tt.equals {
+				t.Errorf("Equals(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.equals)
+			}
+			a32, b32 := Convert[float32](tt.a), Convert[float32](tt.b)
+			if got := EqualNaN(a32, b32); got != tt.want {
+				t.Errorf("EqualNaN(float32 %v, %v) = %v, want %v", a32, b32, got, tt.want)
+			}
+		})
+	}
+}
+
+// megabyte is large enough that copying it dominates any Optional operation.
+type megabyte struct {
+	data [1 << 20]byte
+}
+
+func TestUpdateInPlace(t *testing.T) {
+	o := Of(kilobyte{})
+	var seen *kilobyte
+	o.Update(func(k *kilobyte) {
+		seen = k
+		k.data[0] = 1
+	})
+	if seen != &o.value {
+		t.Error("Update passed a pointer to a copy")
+	}
+	if v, _ := o.Get(); v.data[0] != 1 {
+		t.Error("Update did not modify the stored value")
+	}
+	if allocs := testing.AllocsPerRun(100, func() {
+		o.Update(func(k *kilobyte) { k.data[1]++ })
+	}); allocs != 0 {
+		t.Errorf("Update allocates %v times, want 0", allocs)
+	}
+}
+
+// BenchmarkUpdate compares modifying a large value with Update against
+// copying it out with Get and back in with Of.
+func BenchmarkUpdate(b *testing.B) {
+	b.Run("1KB/Update", func(b *testing.B) {
+		o := Of(kilobyte{})
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			o.Update(func(k *kilobyte) { k.data[0]++ })
+		}
+		sinkOptKilobyte = o
+	})
+	b.Run("1KB/GetOf", func(b *testing.B) {
+		o := Of(kilobyte{})
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			v, _ := o.Get()
+			v.data[0]++
+			o = Of(v)
+		}
+		sinkOptKilobyte = o
+	})
+	b.Run("1MB/Update", func(b *testing.B) {
+		o := new(Optional[megabyte])
+		*o = Of(megabyte{})
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			o.Update(func(m *megabyte) { m.data[0]++ })
+		}
+	})
+	b.Run("1MB/GetOf", func(b *testing.B) {
+		o := new(Optional[megabyte])
+		*o = Of(megabyte{})
+		v := new(megabyte)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			*v, _ = o.Get()
+			v.data[0]++
+			*o = Of(*v)
+		}
+	})
+}
+
+func TestWhenUnless(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Optional[int]
+		want Optional[int]
+	}{
+		{"When true", When(true, 1), Of(1)},
+		{"When false", When(false, 1), Empty[int]()},
+		{"When true zero", When(true, 0), Of(0)},
+		{"Unless false", Unless(false, 1), Of(1)},
+		{"Unless true", Unless(true, 1), Empty[int]()},
+		{"Unless false zero", Unless(false, 0), Of(0)},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestWhenFuncUnlessFunc(t *testing.T) {
+	tests := []struct {
+		name      string
+		call      func(fn func() int) Optional[int]
+		want      Optional[int]
+		wantCalls int
+	}{
+		{"WhenFunc true", func(fn func() int) Optional[int] { return WhenFunc(true, fn) }, Of(7), 1},
+		{"WhenFunc false", func(fn func() int) Optional[int] { return WhenFunc(false, fn) }, Empty[int](), 0},
+		{"UnlessFunc false", func(fn func() int) Optional[int] { return UnlessFunc(false, fn) }, Of(7), 1},
+		{"UnlessFunc true", func(fn func() int) Optional[int] { return UnlessFunc(true, fn) }, Empty[int](), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			got := tt.call(func() int {
+				calls++
+				return 7
+			})
+			if got != tt.want || calls != tt.wantCalls {
+				t.Errorf("got %v after %d calls, want %v after %d", got, calls, tt.want, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWhenFuncGuards(t *testing.T) {
+	type user struct{ Name string }
+	var u *user
+	// fn dereferences u, so calling it while the guard is false would panic.
+	if got := WhenFunc(u != nil, func() string { return u.Name }); got.IsPresent() {
+		t.Errorf("WhenFunc on nil user = %v, want empty", got)
+	}
+	if got := UnlessFunc(u == nil, func() string { return u.Name }); got.IsPresent() {
+		t.Errorf("UnlessFunc on nil user = %v, want empty", got)
+	}
+}
+
+func TestDeref(t *testing.T) {
+	n := 42
+	var nilPtr *int
+	present := Of(nilPtr)
+	if !present.IsPresent() {
+		t.Fatal("Of[*int](nil) is empty, want present holding a nil pointer")
+	}
+	if p, _ := present.Get(); p != nil {
+		t.Fatalf("Of[*int](nil) holds %p, want nil", p)
+	}
+
+	tests := []struct {
+		name string
+		in   Optional[*int]
+		want Optional[int]
+	}{
+		{"pointer", Of(&n), Of(42)},
+		{"nil pointer", present, Empty[int]()},
+		{"empty", Empty[*int](), Empty[int]()},
+	}
+	for _, tt := range tests {
+		if got := Deref(tt.in); got != tt.want {
+			t.Errorf("Deref(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	got := Deref(Of(&n))
+	n = 7
+	if got != Of(42) {
+		t.Errorf("Deref aliases the pointee: got %v after changing it", got)
+	}
+}
+
+func TestRef(t *testing.T) {
+	o := Of(42)
+	r := Ref(o)
+	p, ok := r.Get()
+	if !ok || p == nil || *p != 42 {
+		t.Fatalf("Ref(Of(42)) = %v", r)
+	}
+	*p = 7
+	if o != Of(42) {
+		t.Errorf("writing through Ref's pointer changed the source to %v", o)
+	}
+	if q, _ := Ref(o).Get(); q == p {
+		t.Error("two Ref calls returned the same pointer")
+	}
+
+	if r := Ref(Empty[int]()); r.IsPresent() {
+		t.Errorf("Ref(empty) = %v, want empty", r)
+	}
+	if got := Deref(Ref(Of("x"))); got != Of("x") {
+		t.Errorf("Deref(Ref(x)) = %v, want Optional[x]", got)
+	}
+}
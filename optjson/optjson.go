@@ -0,0 +1,502 @@
+// Package optjson encodes values as JSON with encoding/json semantics, except
+// that struct fields holding an empty Optional are omitted entirely. Classic
+// encoding/json cannot do this: omitempty never treats a struct value as
+// empty, and not every codebase can rely on omitzero yet.
+//
+// Example usage:
+//
+//	type UserResponse struct {
+//	    ID       int                       `json:"id"`
+//	    Nickname optional.Optional[string] `json:"nickname"`
+//	}
+//	body, err := optjson.Marshal(UserResponse{ID: 1}) // {"id":1}
+package optjson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Marshal returns the JSON encoding of v. It follows encoding/json for field
+// names, the "-" tag, omitempty, the string option, embedded structs and
+// values implementing json.Marshaler or encoding.TextMarshaler, which are
+// encoded by encoding/json itself. In addition:
+//
+//   - an empty Optional struct field, or map value, is omitted
+//   - a present Optional is encoded as its value, even with omitempty, so a
+//     present zero value is kept
+//   - the string option on an Optional field applies to its element type, so
+//     Optional[int64] with ",string" is encoded as a quoted number
+//   - an empty Optional anywhere else, such as in a slice, is encoded as null
+//
+// As with encoding/json, a cyclic value yields a *json.UnsupportedValueError.
+//
+// Example:
+//
+//	body, err := optjson.Marshal(resp)
+func Marshal(v any) ([]byte, error) {
+	var buf encodeState
+	if err := encode(&buf, reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// startDetectingCyclesAfter is the nesting depth of pointers, maps and slices
+// from which encodeState looks for cycles. As in encoding/json, the check is
+// skipped for shallower values, which are by far the most common.
+const startDetectingCyclesAfter = 1000
+
+// encodeState is the output of one Marshal call, together with the pointers,
+// maps and slices being encoded on the current path, so that a cyclic value
+// is reported as an error instead of overflowing the stack.
+type encodeState struct {
+	bytes.Buffer
+	ptrLevel uint
+	ptrSeen  map[any]struct{}
+}
+
+// enter records that v, a non-nil pointer, map or slice, is being encoded. It
+// returns a *json.UnsupportedValueError, as encoding/json does, if v is
+// already being encoded further up the path. Every successful enter must be
+// paired with a leave.
+func (e *encodeState) enter(v reflect.Value) error {
+	if e.ptrLevel++; e.ptrLevel <= startDetectingCyclesAfter {
+		return nil
+	}
+	key := cycleKey(v)
+	if _, ok := e.ptrSeen[key]; ok {
+		e.ptrLevel--
+		return &json.UnsupportedValueError{Value: v, Str: fmt.Sprintf("encountered a cycle via %s", v.Type())}
+	}
+	if e.ptrSeen == nil {
+		e.ptrSeen = make(map[any]struct{})
+	}
+	e.ptrSeen[key] = struct{}{}
+	return nil
+}
+
+func (e *encodeState) leave(v reflect.Value) {
+	if e.ptrLevel > startDetectingCyclesAfter {
+		delete(e.ptrSeen, cycleKey(v))
+	}
+	e.ptrLevel--
+}
+
+// cycleKey identifies the memory v refers to. A slice is identified by its
+// length as well, since a subslice sharing the same start is a different
+// value.
+func cycleKey(v reflect.Value) any {
+	if v.Kind() == reflect.Slice {
+		return struct {
+			ptr uintptr
+			len int
+		}{v.Pointer(), v.Len()}
+	}
+	return v.Pointer()
+}
+
+func encode(buf *encodeState, v reflect.Value) error {
+	if !v.IsValid() {
+		buf.WriteString("null")
+		return nil
+	}
+	t := v.Type()
+	if _, ok := optional.ElemType(t); ok {
+		val, present := optional.ReflectGet(v)
+		if !present {
+			buf.WriteString("null")
+			return nil
+		}
+		return encode(buf, val)
+	}
+	if t.Kind() != reflect.Interface {
+		if t.Implements(marshalerType) || t.Implements(textMarshalerType) {
+			return marshalLeaf(buf, v.Interface())
+		}
+		if t.Kind() != reflect.Ptr && v.CanAddr() {
+			pt := reflect.PtrTo(t)
+			if pt.Implements(marshalerType) || pt.Implements(textMarshalerType) {
+				return marshalLeaf(buf, v.Addr().Interface())
+			}
+		}
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		if t.Kind() == reflect.Interface {
+			return encode(buf, v.Elem())
+		}
+		if err := buf.enter(v); err != nil {
+			return err
+		}
+		defer buf.leave(v)
+		return encode(buf, v.Elem())
+	case reflect.Struct:
+		return encodeStruct(buf, v)
+	case reflect.Map:
+		return encodeMap(buf, v)
+	case reflect.Slice:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Byte slices are base64-encoded, unless the element type
+			// marshals itself; encoding/json handles both.
+			return marshalLeaf(buf, v.Interface())
+		}
+		if err := buf.enter(v); err != nil {
+			return err
+		}
+		defer buf.leave(v)
+		return encodeArray(buf, v)
+	case reflect.Array:
+		return encodeArray(buf, v)
+	}
+	return marshalLeaf(buf, v.Interface())
+}
+
+func marshalLeaf(buf *encodeState, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
+func encodeStruct(buf *encodeState, v reflect.Value) error {
+	buf.WriteByte('{')
+	first := true
+	for _, f := range cachedFields(v.Type()) {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok {
+			continue
+		}
+		if f.optional {
+			val, present := optional.ReflectGet(fv)
+			if !present {
+				continue
+			}
+			if f.quoted {
+				// The string option applies to the held value, which an
+				// Optional would otherwise encode as itself.
+				fv = val
+			}
+		} else if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		buf.Write(f.key)
+		buf.WriteByte(':')
+		if f.quoted {
+			if err := encodeQuoted(buf, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := encode(buf, fv); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// encodeQuoted encodes v as a JSON string holding its JSON encoding, for
+// fields with the string option. As in encoding/json, a nil pointer is encoded
+// as null.
+func encodeQuoted(buf *encodeState, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		buf.WriteString("null")
+		return nil
+	}
+	var inner encodeState
+	if err := encode(&inner, v); err != nil {
+		return err
+	}
+	return marshalLeaf(buf, inner.String())
+}
+
+func encodeMap(buf *encodeState, v reflect.Value) error {
+	if v.IsNil() {
+		buf.WriteString("null")
+		return nil
+	}
+	if err := buf.enter(v); err != nil {
+		return err
+	}
+	defer buf.leave(v)
+	_, optionalValues := optional.ElemType(v.Type().Elem())
+	type entry struct {
+		key string
+		val reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		val := iter.Value()
+		if optionalValues {
+			if _, present := optional.ReflectGet(val); !present {
+				continue
+			}
+		}
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry{key: key, val: val})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+	buf.WriteByte('{')
+	for i, e := range entries {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := marshalLeaf(buf, e.key); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if err := encode(buf, e.val); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// mapKey returns the object key for a map key, under the same rules as
+// encoding/json.
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Ptr && k.IsNil() {
+			return "", nil
+		}
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("optjson: unsupported map key type %s", k.Type())
+}
+
+func encodeArray(buf *encodeState, v reflect.Value) error {
+	buf.WriteByte('[')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := encode(buf, v.Index(i)); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but reports false instead
+// of panicking when the path goes through a nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// field describes one encoded struct field.
+type field struct {
+	name      string
+	key       []byte // name encoded as a JSON string
+	index     []int
+	depth     int
+	tagged    bool
+	omitEmpty bool
+	quoted    bool
+	optional  bool
+}
+
+var fieldCache sync.Map // map[reflect.Type][]field
+
+func cachedFields(t reflect.Type) []field {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]field)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.([]field)
+}
+
+// typeFields returns the fields encoded for struct type t, in the order
+// encoding/json uses, resolving name conflicts between embedded fields by the
+// same dominance rules: the shallowest field wins, then the tagged one, and
+// otherwise all conflicting fields are dropped.
+func typeFields(t reflect.Type) []field {
+	var all []field
+	collectFields(t, nil, map[reflect.Type]bool{t: true}, &all)
+
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].name != all[j].name {
+			return all[i].name < all[j].name
+		}
+		if all[i].depth != all[j].depth {
+			return all[i].depth < all[j].depth
+		}
+		return all[i].tagged && !all[j].tagged
+	})
+	fields := all[:0]
+	for i := 0; i < len(all); {
+		j := i + 1
+		for j < len(all) && all[j].name == all[i].name {
+			j++
+		}
+		group := all[i:j]
+		if len(group) == 1 || group[1].depth > group[0].depth || (group[0].tagged && !group[1].tagged) {
+			fields = append(fields, group[0])
+		}
+		i = j
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i].index, fields[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return fields
+}
+
+func collectFields(t reflect.Type, index []int, visiting map[reflect.Type]bool, out *[]field) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		ft := sf.Type
+		if sf.Anonymous {
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if !sf.IsExported() && ft.Kind() != reflect.Struct {
+				continue
+			}
+		} else if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		_, isOptional := optional.ElemType(sf.Type)
+		if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct && !isOptional {
+			// Flatten the embedded struct, guarding against cycles through
+			// embedded pointers.
+			if !visiting[ft] {
+				visiting[ft] = true
+				collectFields(ft, fieldIndex, visiting, out)
+				delete(visiting, ft)
+			}
+			continue
+		}
+		// An unexported embedded struct with a tag name is encoded as a
+		// field, like in encoding/json: its exported fields stay readable.
+		tagged := name != ""
+		if !tagged {
+			name = sf.Name
+		}
+		key, _ := json.Marshal(name)
+		quotedType := sf.Type
+		if elem, ok := optional.ElemType(sf.Type); ok {
+			quotedType = elem
+		}
+		*out = append(*out, field{
+			name:      name,
+			key:       key,
+			index:     fieldIndex,
+			depth:     len(index),
+			tagged:    tagged,
+			omitEmpty: hasOption(opts, "omitempty"),
+			quoted:    hasOption(opts, "string") && quotable(quotedType),
+			optional:  isOptional,
+		})
+	}
+}
+
+func hasOption(opts, name string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+// quotable reports whether the string option applies to fields of type t. For
+// Optional fields, t is the element type.
+func quotable(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
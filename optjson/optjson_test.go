@@ -0,0 +1,268 @@
+package optjson
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+type point struct {
+	X, Y int
+}
+
+type celsius float64
+
+func (c celsius) MarshalText() ([]byte, error) { return []byte("warm"), nil }
+
+type ptrMarshaler struct{ n int }
+
+func (p *ptrMarshaler) MarshalJSON() ([]byte, error) { return []byte(`"ptr"`), nil }
+
+type Inner struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Other struct {
+	Name string
+	Only string
+}
+
+type tagged struct {
+	Renamed  string            `json:"renamed"`
+	Skipped  string            `json:"-"`
+	Dash     string            `json:"-,"`
+	Omit     string            `json:",omitempty"`
+	OmitPtr  *int              `json:"omit_ptr,omitempty"`
+	OmitMap  map[string]int    `json:"omit_map,omitempty"`
+	Quoted   int               `json:"quoted,string"`
+	QuotedP  *bool             `json:"quoted_p,string"`
+	NotQuote []int             `json:"not_quote,string"`
+	Any      any               `json:"any"`
+	Raw      json.RawMessage   `json:"raw"`
+	Number   json.Number       `json:"number"`
+	Time     time.Time         `json:"time"`
+	IP       net.IP            `json:"ip"`
+	Bytes    []byte            `json:"bytes"`
+	Array    [2]string         `json:"array"`
+	IntKeys  map[int]string    `json:"int_keys"`
+	TextKeys map[celsius]int   `json:"text_keys"`
+	Nested   map[string][]bool `json:"nested"`
+	Ptr      *ptrMarshaler     `json:"ptr"`
+	Val      ptrMarshaler      `json:"val"`
+	unexp    int
+}
+
+type embedding struct {
+	Inner
+	*Other
+	Name  string `json:"name"`
+	point `json:"point"`
+}
+
+// Values without Optionals must encode exactly as with encoding/json.
+func TestMarshalMatchesEncodingJSON(t *testing.T) {
+	n := 7
+	yes := true
+	tests := map[string]any{
+		"nil":            nil,
+		"bool":           true,
+		"int":            -42,
+		"uint64":         uint64(math.MaxUint64),
+		"float":          3.14,
+		"float exponent": []float64{1e21, 1e-7, 123456789, math.Copysign(0, -1), math.SmallestNonzeroFloat64},
+		"float32":        float32(0.1),
+		"html":           "<a href=\"x\">&</a>",
+		"unicode":        "ünïcode     \x00 \x1f �",
+		"invalid utf8":   "\xff",
+		"nil slice":      []int(nil),
+		"empty slice":    []int{},
+		"nil map":        map[string]int(nil),
+		"sorted map":     map[string]int{"b": 2, "a": 1, "c": 3},
+		"nil pointer":    (*int)(nil),
+		"pointer":        &n,
+		"struct":         point{1, 2},
+		"anonymous":      struct{ A, b int }{1, 2},
+		"zero tagged":    tagged{},
+		"tagged": &tagged{
+			Renamed: "r", Skipped: "s", Dash: "d", Omit: "o", OmitPtr: &n,
+			OmitMap: map[string]int{"k": 1}, Quoted: 5, QuotedP: &yes,
+			NotQuote: []int{1}, Any: []any{1, "x", nil}, Raw: json.RawMessage(`{"raw":true}`),
+			Number: "12.5", Time: time.Date(2024, 5, 6, 7, 8, 9, 10, time.UTC),
+			IP: net.IPv4(10, 0, 0, 1), Bytes: []byte("hello"), Array: [2]string{"a", "b"},
+			IntKeys: map[int]string{-1: "neg", 10: "ten", 2: "two"}, TextKeys: map[celsius]int{1: 1},
+			Nested: map[string][]bool{"x": {true}}, Ptr: &ptrMarshaler{}, unexp: 1,
+		},
+		"embedding":             embedding{Inner: Inner{ID: 1, Name: "inner"}, Other: &Other{Name: "other", Only: "o"}, Name: "outer"},
+		"nil embedded pointer":  embedding{Inner: Inner{ID: 1}},
+		"addressable marshaler": &struct{ V ptrMarshaler }{},
+		"interface slice":       []any{map[string]any{"z": 1, "a": []any{}}, 1.5, "s", nil},
+	}
+	for name, v := range tests {
+		t.Run(name, func(t *testing.T) {
+			want, err := json.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Marshal(v)
+			if err != nil {
+				t.Fatalf("Marshal error = %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("Marshal = %s\nencoding/json = %s", got, want)
+			}
+		})
+	}
+}
+
+func TestMarshalOptional(t *testing.T) {
+	type user struct {
+		ID       int                       `json:"id"`
+		Nickname optional.Optional[string] `json:"nickname"`
+		Age      optional.Optional[int]    `json:"age,omitempty"`
+		Tags     optional.Optional[[]string]
+		Ptr      *optional.Optional[bool] `json:"ptr,omitempty"`
+		Skipped  optional.Optional[int]   `json:"-"`
+	}
+	present := optional.Of(false)
+	tests := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"empty fields omitted", user{ID: 1}, `{"id":1}`},
+		{"present zero values kept", user{Nickname: optional.Of(""), Age: optional.Of(0), Tags: optional.Of([]string(nil))},
+			`{"id":0,"nickname":"","age":0,"Tags":null}`},
+		{"pointer to Optional", user{Ptr: &present}, `{"id":0,"ptr":false}`},
+		{"skipped", user{Skipped: optional.Of(1)}, `{"id":0}`},
+		{"top-level empty", optional.Empty[int](), `null`},
+		{"top-level present", optional.Of("x"), `"x"`},
+		{"slice elements", []optional.Optional[int]{optional.Of(1), optional.Empty[int](), optional.Of(0)}, `[1,null,0]`},
+		{"map values", map[string]optional.Optional[int]{"a": optional.Of(1), "b": optional.Empty[int](), "c": optional.Of(0)}, `{"a":1,"c":0}`},
+		{"empty map values", map[string]optional.Optional[int]{"b": optional.Empty[int]()}, `{}`},
+		{"nested", optional.Of(user{Nickname: optional.Of("n")}), `{"id":0,"nickname":"n"}`},
+		{"quoted", struct {
+			N optional.Optional[int] `json:"n,string"`
+		}{optional.Of(3)}, `{"n":"3"}`},
+		{"quoted int64", struct {
+			N optional.Optional[int64] `json:"n,string"`
+		}{optional.Of(int64(math.MaxInt64))}, `{"n":"9223372036854775807"}`},
+		{"quoted empty", struct {
+			N optional.Optional[int64] `json:"n,string"`
+		}{}, `{}`},
+		{"quoted string", struct {
+			S optional.Optional[string] `json:"s,string"`
+		}{optional.Of("x")}, `{"s":"\"x\""}`},
+		{"quoted nil pointer", struct {
+			P optional.Optional[*int] `json:"p,string"`
+		}{optional.Of[*int](nil)}, `{"p":null}`},
+		{"not quotable", struct {
+			L optional.Optional[[]int] `json:"l,string"`
+		}{optional.Of([]int{1})}, `{"l":[1]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Marshal(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalOptionalQuotedMatchesPlain(t *testing.T) {
+	type plain struct {
+		ID    int64   `json:"id,string"`
+		Ratio float64 `json:"ratio,string"`
+		OK    *bool   `json:"ok,string"`
+	}
+	type opt struct {
+		ID    optional.Optional[int64]   `json:"id,string"`
+		Ratio optional.Optional[float64] `json:"ratio,string"`
+		OK    optional.Optional[*bool]   `json:"ok,string"`
+	}
+	yes := true
+	want, err := json.Marshal(plain{ID: -1 << 60, Ratio: 0.5, OK: &yes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Marshal(opt{ID: optional.Of(int64(-1 << 60)), Ratio: optional.Of(0.5), OK: optional.Of(&yes)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Marshal = %s\nencoding/json = %s", got, want)
+	}
+}
+
+type node struct {
+	Value int                    `json:"value"`
+	Next  *node                  `json:"next,omitempty"`
+	Opt   optional.Optional[any] `json:"opt"`
+}
+
+func TestMarshalCycle(t *testing.T) {
+	self := &node{Value: 1}
+	self.Next = self
+
+	viaOptional := &node{Value: 2}
+	viaOptional.Opt = optional.Of[any](viaOptional)
+
+	m := map[string]any{}
+	m["self"] = m
+
+	s := make([]any, 1)
+	s[0] = s
+
+	tests := map[string]any{
+		"pointer":          self,
+		"through Optional": viaOptional,
+		"map":              m,
+		"slice":            s,
+	}
+	for name, v := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Marshal(v)
+			var unsupported *json.UnsupportedValueError
+			if !errors.As(err, &unsupported) || !strings.Contains(err.Error(), "encountered a cycle") {
+				t.Errorf("Marshal error = %v, want a cycle *json.UnsupportedValueError", err)
+			}
+		})
+	}
+}
+
+// Deep values without cycles must still encode, past the depth at which cycle
+// detection starts.
+func TestMarshalDeepAcyclic(t *testing.T) {
+	var list *node
+	for i := 0; i < 3*startDetectingCyclesAfter; i++ {
+		list = &node{Value: i, Next: list}
+	}
+	got, err := Marshal(list)
+	if err != nil {
+		t.Fatalf("Marshal error = %v", err)
+	}
+	if !strings.HasPrefix(string(got), `{"value":2999,"next":{"value":2998,`) || !strings.HasSuffix(string(got), `{"value":0}`+strings.Repeat("}", 3*startDetectingCyclesAfter-1)) {
+		t.Errorf("Marshal = %.80s...", got)
+	}
+
+	// The same slice appearing twice side by side is not a cycle.
+	shared := []int{1}
+	var deep any = [][]int{shared, shared}
+	for i := 0; i < startDetectingCyclesAfter; i++ {
+		deep = []any{deep}
+	}
+	want, _ := json.Marshal(deep)
+	if got, err := Marshal(deep); err != nil || string(got) != string(want) {
+		t.Errorf("Marshal of a deep shared slice = %.40s, %v", got, err)
+	}
+}
@@ -0,0 +1,119 @@
+// Package optmath provides arithmetic over Optional numbers in which empty
+// propagates: an operation with an empty operand has an empty result, rather
+// than treating the missing value as zero.
+//
+// Aggregation comes in two clearly separated modes. Sum propagates empty, so a
+// single missing amount makes the total missing; SumPresent ignores empty
+// operands and totals whatever is there.
+//
+// Example usage:
+//
+//	net := optmath.Sub(invoice.Gross, invoice.Discount) // empty if either is missing
+//	total := optmath.Sum(line1, line2, line3)           // empty if any line is missing
+//	known := optmath.SumPresent(line1, line2, line3)    // total of the lines that exist
+package optmath
+
+import (
+	"errors"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// ErrDivisionByZero is returned by TryDiv when the divisor is present and zero.
+var ErrDivisionByZero = errors.New("optmath: division by zero")
+
+// Add returns a + b, or an empty Optional if either operand is empty.
+func Add[T optional.Number](a, b optional.Optional[T]) optional.Optional[T] {
+	return apply(a, b, func(x, y T) T { return x + y })
+}
+
+// Sub returns a - b, or an empty Optional if either operand is empty.
+func Sub[T optional.Number](a, b optional.Optional[T]) optional.Optional[T] {
+	return apply(a, b, func(x, y T) T { return x - y })
+}
+
+// Mul returns a * b, or an empty Optional if either operand is empty.
+func Mul[T optional.Number](a, b optional.Optional[T]) optional.Optional[T] {
+	return apply(a, b, func(x, y T) T { return x * y })
+}
+
+// Div returns a / b, or an empty Optional if either operand is empty or b is
+// zero. A zero divisor yields empty for floating-point types as well, instead
+// of ±Inf or NaN; use TryDiv to tell it apart from a missing operand.
+//
+// Example:
+//
+//	avg := optmath.Div(total, count) // empty if count is missing or zero
+func Div[T optional.Number](a, b optional.Optional[T]) optional.Optional[T] {
+	o, _ := TryDiv(a, b)
+	return o
+}
+
+// TryDiv is like Div, but returns ErrDivisionByZero when b is present and zero.
+// If either operand is empty, the result is empty and the error is nil.
+func TryDiv[T optional.Number](a, b optional.Optional[T]) (optional.Optional[T], error) {
+	x, ok := a.Get()
+	if !ok {
+		return optional.Empty[T](), nil
+	}
+	y, ok := b.Get()
+	if !ok {
+		return optional.Empty[T](), nil
+	}
+	if y == 0 {
+		return optional.Empty[T](), ErrDivisionByZero
+	}
+	return optional.Of(x / y), nil
+}
+
+// Sum returns the sum of opts, or an empty Optional if any of them is empty.
+// The sum of no operands is a present zero.
+//
+// Example:
+//
+//	total := optmath.Sum(order.Subtotal, order.Shipping, order.Tax)
+func Sum[T optional.Number](opts ...optional.Optional[T]) optional.Optional[T] {
+	var sum T
+	for _, o := range opts {
+		val, ok := o.Get()
+		if !ok {
+			return optional.Empty[T]()
+		}
+		sum += val
+	}
+	return optional.Of(sum)
+}
+
+// SumPresent returns the sum of the present values in opts, ignoring empty
+// ones. It returns an empty Optional only if no operand is present, so that
+// "nothing to add up" stays distinguishable from a total of zero.
+//
+// Example:
+//
+//	collected := optmath.SumPresent(payments...)
+func SumPresent[T optional.Number](opts ...optional.Optional[T]) optional.Optional[T] {
+	var sum T
+	present := false
+	for _, o := range opts {
+		if val, ok := o.Get(); ok {
+			sum += val
+			present = true
+		}
+	}
+	if !present {
+		return optional.Empty[T]()
+	}
+	return optional.Of(sum)
+}
+
+func apply[T optional.Number](a, b optional.Optional[T], op func(T, T) T) optional.Optional[T] {
+	x, ok := a.Get()
+	if !ok {
+		return optional.Empty[T]()
+	}
+	y, ok := b.Get()
+	if !ok {
+		return optional.Empty[T]()
+	}
+	return optional.Of(op(x, y))
+}
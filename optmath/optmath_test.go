@@ -0,0 +1,111 @@
+package optmath
+
+import (
+	"math"
+	"testing"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+func TestArithmetic(t *testing.T) {
+	three, four, none := optional.Of(3), optional.Of(4), optional.Empty[int]()
+	tests := []struct {
+		name string
+		op   func(a, b optional.Optional[int]) optional.Optional[int]
+		a, b optional.Optional[int]
+		want optional.Optional[int]
+	}{
+		{"add", Add[int], three, four, optional.Of(7)},
+		{"add empty a", Add[int], none, four, none},
+		{"add empty b", Add[int], three, none, none},
+		{"sub", Sub[int], three, four, optional.Of(-1)},
+		{"sub empty", Sub[int], none, none, none},
+		{"mul", Mul[int], three, four, optional.Of(12)},
+		{"mul zero", Mul[int], three, optional.Of(0), optional.Of(0)},
+		{"mul empty", Mul[int], three, none, none},
+		{"div", Div[int], optional.Of(9), four, optional.Of(2)},
+		{"div empty", Div[int], none, four, none},
+	}
+	for _, tt := range tests {
+		if got := tt.op(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s(%v, %v) = %v, want %v", tt.name, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+type divCase[T optional.Number] struct {
+	a, b    optional.Optional[T]
+	want    optional.Optional[T]
+	wantErr error
+}
+
+func (c divCase[T]) run(t *testing.T) {
+	t.Helper()
+	got, err := TryDiv(c.a, c.b)
+	if got != c.want || err != c.wantErr {
+		t.Errorf("TryDiv(%v, %v) = %v, %v, want %v, %v", c.a, c.b, got, err, c.want, c.wantErr)
+	}
+	if got := Div(c.a, c.b); got != c.want {
+		t.Errorf("Div(%v, %v) = %v, want %v", c.a, c.b, got, c.want)
+	}
+}
+
+func TestDiv(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(*testing.T)
+	}{
+		{"int", divCase[int]{a: optional.Of(7), b: optional.Of(2), want: optional.Of(3)}.run},
+		{"int by zero", divCase[int]{a: optional.Of(7), b: optional.Of(0), want: optional.Empty[int](), wantErr: ErrDivisionByZero}.run},
+		{"int zero by zero", divCase[int]{a: optional.Of(0), b: optional.Of(0), want: optional.Empty[int](), wantErr: ErrDivisionByZero}.run},
+		{"int empty divisor", divCase[int]{a: optional.Of(7), b: optional.Empty[int](), want: optional.Empty[int]()}.run},
+		{"int empty dividend by zero", divCase[int]{a: optional.Empty[int](), b: optional.Of(0), want: optional.Empty[int]()}.run},
+		{"uint8 by zero", divCase[uint8]{a: optional.Of(uint8(200)), b: optional.Of(uint8(0)), want: optional.Empty[uint8](), wantErr: ErrDivisionByZero}.run},
+		{"int64 min by -1", divCase[int64]{a: optional.Of(int64(math.MinInt64)), b: optional.Of(int64(-1)), want: optional.Of(int64(math.MinInt64))}.run},
+		{"float", divCase[float64]{a: optional.Of(1.0), b: optional.Of(4.0), want: optional.Of(0.25)}.run},
+		{"float by zero", divCase[float64]{a: optional.Of(1.0), b: optional.Of(0.0), want: optional.Empty[float64](), wantErr: ErrDivisionByZero}.run},
+		{"float by negative zero", divCase[float64]{a: optional.Of(1.0), b: optional.Of(math.Copysign(0, -1)), want: optional.Empty[float64](), wantErr: ErrDivisionByZero}.run},
+		{"float zero by zero", divCase[float64]{a: optional.Of(0.0), b: optional.Of(0.0), want: optional.Empty[float64](), wantErr: ErrDivisionByZero}.run},
+		{"float32 by zero", divCase[float32]{a: optional.Of(float32(1)), b: optional.Of(float32(0)), want: optional.Empty[float32](), wantErr: ErrDivisionByZero}.run},
+		{"float empty", divCase[float64]{a: optional.Empty[float64](), b: optional.Empty[float64](), want: optional.Empty[float64]()}.run},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
+
+func TestSum(t *testing.T) {
+	none := optional.Empty[int]()
+	tests := []struct {
+		name        string
+		in          []optional.Optional[int]
+		sum, sumAll optional.Optional[int]
+	}{
+		{"all present", []optional.Optional[int]{optional.Of(1), optional.Of(2), optional.Of(3)}, optional.Of(6), optional.Of(6)},
+		{"one empty", []optional.Optional[int]{optional.Of(1), none, optional.Of(3)}, none, optional.Of(4)},
+		{"all empty", []optional.Optional[int]{none, none}, none, none},
+		{"present zero", []optional.Optional[int]{optional.Of(0), none}, none, optional.Of(0)},
+		{"single", []optional.Optional[int]{optional.Of(5)}, optional.Of(5), optional.Of(5)},
+		{"no operands", nil, optional.Of(0), none},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sum(tt.in...); got != tt.sum {
+				t.Errorf("Sum = %v, want %v", got, tt.sum)
+			}
+			if got := SumPresent(tt.in...); got != tt.sumAll {
+				t.Errorf("SumPresent = %v, want %v", got, tt.sumAll)
+			}
+		})
+	}
+}
+
+func TestSumFloat(t *testing.T) {
+	in := []optional.Optional[float64]{optional.Of(0.5), optional.Empty[float64](), optional.Of(0.25)}
+	if got := Sum(in...); got.IsPresent() {
+		t.Errorf("Sum = %v, want empty", got)
+	}
+	if got := SumPresent(in...); got != optional.Of(0.75) {
+		t.Errorf("SumPresent = %v, want Optional[0.75]", got)
+	}
+}
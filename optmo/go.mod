@@ -0,0 +1,10 @@
+module github.com/sergei-bronnikov/go-optional/optmo
+
+go 1.18
+
+require (
+	github.com/samber/mo v1.17.0
+	github.com/sergei-bronnikov/go-optional v0.0.0
+)
+
+replace github.com/sergei-bronnikov/go-optional => ../
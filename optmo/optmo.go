@@ -0,0 +1,30 @@
+// Package optmo converts between Optionals and samber/mo Options, so code bases
+// using both can migrate gradually. Presence is preserved exactly in both
+// directions, including present zero values.
+//
+// Example usage:
+//
+//	opt := optmo.FromMo(mo.Some(42)) // Optional[42]
+//	option := optmo.ToMo(opt)        // mo.Some(42)
+package optmo
+
+import (
+	"github.com/samber/mo"
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// FromMo converts a mo.Option into an Optional.
+func FromMo[T any](o mo.Option[T]) optional.Optional[T] {
+	if val, ok := o.Get(); ok {
+		return optional.Of(val)
+	}
+	return optional.Empty[T]()
+}
+
+// ToMo converts an Optional into a mo.Option.
+func ToMo[T any](o optional.Optional[T]) mo.Option[T] {
+	if val, ok := o.Get(); ok {
+		return mo.Some(val)
+	}
+	return mo.None[T]()
+}
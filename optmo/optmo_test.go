@@ -0,0 +1,76 @@
+package optmo
+
+import (
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/samber/mo"
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+type record struct {
+	Name string
+	Tags []string
+	Age  uint8
+}
+
+func roundTrips[T any](t *testing.T) {
+	t.Helper()
+	toMo := func(v T, present bool) bool {
+		o := optional.When(present, v)
+		back := FromMo(ToMo(o))
+		return reflect.DeepEqual(back, o)
+	}
+	if err := quick.Check(toMo, nil); err != nil {
+		t.Errorf("Optional -> mo -> Optional: %v", err)
+	}
+	fromMo := func(v T, present bool) bool {
+		m := mo.None[T]()
+		if present {
+			m = mo.Some(v)
+		}
+		back := ToMo(FromMo(m))
+		return back.IsPresent() == m.IsPresent() && reflect.DeepEqual(back.OrEmpty(), m.OrEmpty())
+	}
+	if err := quick.Check(fromMo, nil); err != nil {
+		t.Errorf("mo -> Optional -> mo: %v", err)
+	}
+	presence := func(v T, present bool) bool {
+		o := optional.When(present, v)
+		m := ToMo(o)
+		got, ok := m.Get()
+		want, _ := o.Get()
+		return ok == present && reflect.DeepEqual(got, want)
+	}
+	if err := quick.Check(presence, nil); err != nil {
+		t.Errorf("presence: %v", err)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Run("int", roundTrips[int])
+	t.Run("string", roundTrips[string])
+	t.Run("float64", roundTrips[float64])
+	t.Run("bool", roundTrips[bool])
+	t.Run("[]byte", roundTrips[[]byte])
+	t.Run("struct", roundTrips[record])
+}
+
+func TestPresentZeroValues(t *testing.T) {
+	if m := ToMo(optional.Of(0)); !m.IsPresent() {
+		t.Error("ToMo(Of(0)) is None")
+	}
+	if o := FromMo(mo.Some("")); !o.IsPresent() {
+		t.Error("FromMo(Some(\"\")) is empty")
+	}
+	if o := FromMo(mo.Some[*int](nil)); !o.IsPresent() {
+		t.Error("FromMo(Some(nil)) is empty")
+	}
+	if m := ToMo(optional.Empty[int]()); m.IsPresent() {
+		t.Error("ToMo(Empty) is Some")
+	}
+	if o := FromMo(mo.None[int]()); o.IsPresent() {
+		t.Error("FromMo(None) is present")
+	}
+}
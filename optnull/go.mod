@@ -0,0 +1,10 @@
+module github.com/sergei-bronnikov/go-optional/optnull
+
+go 1.21.4
+
+require (
+	github.com/guregu/null/v5 v5.0.0
+	github.com/sergei-bronnikov/go-optional v0.0.0
+)
+
+replace github.com/sergei-bronnikov/go-optional => ../
@@ -0,0 +1,101 @@
+// Package optnull converts between Optionals and the nullable types from
+// github.com/guregu/null. The Valid flag maps exactly onto presence, so a valid
+// zero value such as null.String{String: "", Valid: true} becomes a present
+// empty string, and only an invalid value becomes an empty Optional.
+//
+// Example usage:
+//
+//	nickname := optnull.FromNullString(row.Nickname)
+//	row.Nickname = optnull.ToNullString(user.Nickname)
+package optnull
+
+import (
+	"time"
+
+	"github.com/guregu/null/v5"
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// FromNullString converts a null.String into an Optional that is present if and only if v is valid.
+func FromNullString(v null.String) optional.Optional[string] {
+	if !v.Valid {
+		return optional.Empty[string]()
+	}
+	return optional.Of(v.String)
+}
+
+// ToNullString converts an Optional into a null.String that is valid if and only if o is present.
+func ToNullString(o optional.Optional[string]) null.String {
+	val, ok := o.Get()
+	return null.NewString(val, ok)
+}
+
+// FromNullInt converts a null.Int into an Optional that is present if and only if v is valid.
+func FromNullInt(v null.Int) optional.Optional[int64] {
+	if !v.Valid {
+		return optional.Empty[int64]()
+	}
+	return optional.Of(v.Int64)
+}
+
+// ToNullInt converts an Optional into a null.Int that is valid if and only if o is present.
+func ToNullInt(o optional.Optional[int64]) null.Int {
+	val, ok := o.Get()
+	return null.NewInt(val, ok)
+}
+
+// FromNullFloat converts a null.Float into an Optional that is present if and only if v is valid.
+func FromNullFloat(v null.Float) optional.Optional[float64] {
+	if !v.Valid {
+		return optional.Empty[float64]()
+	}
+	return optional.Of(v.Float64)
+}
+
+// ToNullFloat converts an Optional into a null.Float that is valid if and only if o is present.
+func ToNullFloat(o optional.Optional[float64]) null.Float {
+	val, ok := o.Get()
+	return null.NewFloat(val, ok)
+}
+
+// FromNullBool converts a null.Bool into an Optional that is present if and only if v is valid.
+func FromNullBool(v null.Bool) optional.Optional[bool] {
+	if !v.Valid {
+		return optional.Empty[bool]()
+	}
+	return optional.Of(v.Bool)
+}
+
+// ToNullBool converts an Optional into a null.Bool that is valid if and only if o is present.
+func ToNullBool(o optional.Optional[bool]) null.Bool {
+	val, ok := o.Get()
+	return null.NewBool(val, ok)
+}
+
+// FromNullTime converts a null.Time into an Optional that is present if and only if v is valid.
+func FromNullTime(v null.Time) optional.Optional[time.Time] {
+	if !v.Valid {
+		return optional.Empty[time.Time]()
+	}
+	return optional.Of(v.Time)
+}
+
+// ToNullTime converts an Optional into a null.Time that is valid if and only if o is present.
+func ToNullTime(o optional.Optional[time.Time]) null.Time {
+	val, ok := o.Get()
+	return null.NewTime(val, ok)
+}
+
+// FromNullValue converts a generic null.Value into an Optional that is present if and only if v is valid.
+func FromNullValue[T any](v null.Value[T]) optional.Optional[T] {
+	if !v.Valid {
+		return optional.Empty[T]()
+	}
+	return optional.Of(v.V)
+}
+
+// ToNullValue converts an Optional into a generic null.Value that is valid if and only if o is present.
+func ToNullValue[T any](o optional.Optional[T]) null.Value[T] {
+	val, ok := o.Get()
+	return null.NewValue(val, ok)
+}
@@ -0,0 +1,98 @@
+package optnull
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/guregu/null/v5"
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// nullCase checks one null type's From and To functions: every value,
+// including the zero value, must survive a round trip in both directions with
+// Valid mapped exactly to presence.
+type nullCase[T any, N any] struct {
+	from    func(N) optional.Optional[T]
+	to      func(optional.Optional[T]) N
+	newNull func(T, bool) N
+	get     func(N) (T, bool)
+	values  []T
+}
+
+func (c nullCase[T, N]) run(t *testing.T) {
+	var zero T
+	for _, v := range append([]T{zero}, c.values...) {
+		for _, valid := range []bool{true, false} {
+			n := c.newNull(v, valid)
+			o := c.from(n)
+			want := optional.When(valid, v)
+			if !reflect.DeepEqual(o, want) {
+				t.Errorf("From(%v, valid=%v) = %v, want %v", v, valid, o, want)
+			}
+			got, ok := c.get(c.to(o))
+			wantVal, _ := want.Get()
+			if ok != valid || !reflect.DeepEqual(got, wantVal) {
+				t.Errorf("To(From(%v, valid=%v)) = %v, %v", v, valid, got, ok)
+			}
+			if back := c.from(c.to(want)); !reflect.DeepEqual(back, want) {
+				t.Errorf("round trip of %v = %v", want, back)
+			}
+		}
+	}
+}
+
+func TestConversions(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	tests := map[string]interface{ run(*testing.T) }{
+		"String": nullCase[string, null.String]{
+			FromNullString, ToNullString, null.NewString,
+			func(n null.String) (string, bool) { return n.String, n.Valid },
+			[]string{"hello"},
+		},
+		"Int": nullCase[int64, null.Int]{
+			FromNullInt, ToNullInt, null.NewInt,
+			func(n null.Int) (int64, bool) { return n.Int64, n.Valid },
+			[]int64{-1, 1 << 62},
+		},
+		"Float": nullCase[float64, null.Float]{
+			FromNullFloat, ToNullFloat, null.NewFloat,
+			func(n null.Float) (float64, bool) { return n.Float64, n.Valid },
+			[]float64{-1.5, 1e300},
+		},
+		"Bool": nullCase[bool, null.Bool]{
+			FromNullBool, ToNullBool, null.NewBool,
+			func(n null.Bool) (bool, bool) { return n.Bool, n.Valid },
+			[]bool{true},
+		},
+		"Time": nullCase[time.Time, null.Time]{
+			FromNullTime, ToNullTime, null.NewTime,
+			func(n null.Time) (time.Time, bool) { return n.Time, n.Valid },
+			[]time.Time{now},
+		},
+		"Value": nullCase[[]string, null.Value[[]string]]{
+			FromNullValue[[]string], ToNullValue[[]string], null.NewValue[[]string],
+			func(n null.Value[[]string]) ([]string, bool) { return n.V, n.Valid },
+			[][]string{{"a", "b"}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.run)
+	}
+}
+
+// A valid zero value is a present value, not an empty Optional.
+func TestValidZeroIsPresent(t *testing.T) {
+	if o := FromNullString(null.StringFrom("")); !o.Equals(optional.Of("")) {
+		t.Errorf("FromNullString(valid \"\") = %v, want present empty string", o)
+	}
+	if o := FromNullInt(null.IntFrom(0)); !o.Equals(optional.Of(int64(0))) {
+		t.Errorf("FromNullInt(valid 0) = %v", o)
+	}
+	if n := ToNullBool(optional.Of(false)); !n.Valid {
+		t.Error("ToNullBool(Of(false)) is not valid")
+	}
+	if n := ToNullTime(optional.Of(time.Time{})); !n.Valid {
+		t.Error("ToNullTime(Of(zero time)) is not valid")
+	}
+}
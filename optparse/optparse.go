@@ -0,0 +1,122 @@
+// Package optparse provides Optional constructors wrapping strconv and
+// encoding.TextUnmarshaler, for turning query parameters, environment
+// variables and CSV cells into Optionals.
+//
+// All functions trim surrounding whitespace before parsing, and a string that
+// is empty after trimming yields an empty Optional rather than an error. The
+// Parse functions also return an empty Optional when parsing fails; the
+// TryParse variants return the parse error instead, for callers that need to
+// distinguish absent input from malformed input.
+//
+// Example usage:
+//
+//	limit := optparse.ParseTo[int](r.URL.Query().Get("limit"))
+//	debug, err := optparse.TryParseBool(os.Getenv("DEBUG"))
+package optparse
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// ParseInt parses s as a signed integer in the given base and bit size, as
+// strconv.ParseInt does.
+func ParseInt(s string, base int, bitSize int) optional.Optional[int64] {
+	o, _ := TryParseInt(s, base, bitSize)
+	return o
+}
+
+// TryParseInt is like ParseInt, but returns the parse error.
+func TryParseInt(s string, base int, bitSize int) (optional.Optional[int64], error) {
+	return try(s, func(s string) (int64, error) {
+		return strconv.ParseInt(s, base, bitSize)
+	})
+}
+
+// ParseUint parses s as an unsigned integer in the given base and bit size, as
+// strconv.ParseUint does.
+func ParseUint(s string, base int, bitSize int) optional.Optional[uint64] {
+	o, _ := TryParseUint(s, base, bitSize)
+	return o
+}
+
+// TryParseUint is like ParseUint, but returns the parse error.
+func TryParseUint(s string, base int, bitSize int) (optional.Optional[uint64], error) {
+	return try(s, func(s string) (uint64, error) {
+		return strconv.ParseUint(s, base, bitSize)
+	})
+}
+
+// ParseFloat parses s as a floating-point number of the given bit size, as
+// strconv.ParseFloat does.
+func ParseFloat(s string, bitSize int) optional.Optional[float64] {
+	o, _ := TryParseFloat(s, bitSize)
+	return o
+}
+
+// TryParseFloat is like ParseFloat, but returns the parse error.
+func TryParseFloat(s string, bitSize int) (optional.Optional[float64], error) {
+	return try(s, func(s string) (float64, error) {
+		return strconv.ParseFloat(s, bitSize)
+	})
+}
+
+// ParseBool parses s as a boolean, accepting the same values as strconv.ParseBool.
+func ParseBool(s string) optional.Optional[bool] {
+	o, _ := TryParseBool(s)
+	return o
+}
+
+// TryParseBool is like ParseBool, but returns the parse error.
+func TryParseBool(s string) (optional.Optional[bool], error) {
+	return try(s, strconv.ParseBool)
+}
+
+// ParseTo parses s as a base-10 number of type T. The value must fit T exactly:
+// out-of-range input yields an empty Optional.
+//
+// Example:
+//
+//	port := optparse.ParseTo[uint16](os.Getenv("PORT"))
+func ParseTo[T optional.Number](s string) optional.Optional[T] {
+	o, _ := TryParseTo[T](s)
+	return o
+}
+
+// TryParseTo is like ParseTo, but returns the parse error.
+func TryParseTo[T optional.Number](s string) (optional.Optional[T], error) {
+	var zero T
+	t := reflect.TypeOf(zero)
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return try(s, func(s string) (T, error) {
+			n, err := strconv.ParseInt(s, 10, t.Bits())
+			return T(n), err
+		})
+	case reflect.Float32, reflect.Float64:
+		return try(s, func(s string) (T, error) {
+			f, err := strconv.ParseFloat(s, t.Bits())
+			return T(f), err
+		})
+	default:
+		return try(s, func(s string) (T, error) {
+			n, err := strconv.ParseUint(s, 10, t.Bits())
+			return T(n), err
+		})
+	}
+}
+
+func try[T any](s string, parse func(string) (T, error)) (optional.Optional[T], error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return optional.Empty[T](), nil
+	}
+	val, err := parse(s)
+	if err != nil {
+		return optional.Empty[T](), err
+	}
+	return optional.Of(val), nil
+}
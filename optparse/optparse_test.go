@@ -0,0 +1,150 @@
+package optparse
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// parseCase checks a Try* function against its lossy counterpart: the lossy
+// variant must return the same Optional, with any error dropped.
+type parseCase[T comparable] struct {
+	in   string
+	want optional.Optional[T]
+	err  error // wrapped strconv error, nil for success
+}
+
+func (c parseCase[T]) check(t *testing.T, try func(string) (optional.Optional[T], error), lossy func(string) optional.Optional[T]) {
+	t.Helper()
+	got, err := try(c.in)
+	if got != c.want {
+		t.Errorf("Try(%q) = %v, want %v", c.in, got, c.want)
+	}
+	if c.err == nil && err != nil || c.err != nil && !errors.Is(err, c.err) {
+		t.Errorf("Try(%q) error = %v, want %v", c.in, err, c.err)
+	}
+	var numErr *strconv.NumError
+	if c.err != nil && !errors.As(err, &numErr) {
+		t.Errorf("Try(%q) error = %T, want the *strconv.NumError itself", c.in, err)
+	}
+	if lossy := lossy(c.in); lossy != c.want {
+		t.Errorf("lossy(%q) = %v, want %v", c.in, lossy, c.want)
+	}
+}
+
+func TestParseInt(t *testing.T) {
+	try := func(s string) (optional.Optional[int64], error) { return TryParseInt(s, 0, 16) }
+	lossy := func(s string) optional.Optional[int64] { return ParseInt(s, 0, 16) }
+	for _, c := range []parseCase[int64]{
+		{"42", optional.Of(int64(42)), nil},
+		{" -0x10\t", optional.Of(int64(-16)), nil},
+		{"0", optional.Of(int64(0)), nil},
+		{"", optional.Empty[int64](), nil},
+		{"  \n", optional.Empty[int64](), nil},
+		{"4 2", optional.Empty[int64](), strconv.ErrSyntax},
+		{"32768", optional.Empty[int64](), strconv.ErrRange},
+	} {
+		c.check(t, try, lossy)
+	}
+}
+
+func TestParseUint(t *testing.T) {
+	try := func(s string) (optional.Optional[uint64], error) { return TryParseUint(s, 2, 8) }
+	lossy := func(s string) optional.Optional[uint64] { return ParseUint(s, 2, 8) }
+	for _, c := range []parseCase[uint64]{
+		{"1010", optional.Of(uint64(10)), nil},
+		{"", optional.Empty[uint64](), nil},
+		{"102", optional.Empty[uint64](), strconv.ErrSyntax},
+		{"-1", optional.Empty[uint64](), strconv.ErrSyntax},
+		{"100000000", optional.Empty[uint64](), strconv.ErrRange},
+	} {
+		c.check(t, try, lossy)
+	}
+}
+
+func TestParseFloat(t *testing.T) {
+	try := func(s string) (optional.Optional[float64], error) { return TryParseFloat(s, 64) }
+	lossy := func(s string) optional.Optional[float64] { return ParseFloat(s, 64) }
+	for _, c := range []parseCase[float64]{
+		{"1.5", optional.Of(1.5), nil},
+		{" 1e3 ", optional.Of(1000.0), nil},
+		{"-0", optional.Of(math.Copysign(0, -1)), nil},
+		{"inf", optional.Of(math.Inf(1)), nil},
+		{"", optional.Empty[float64](), nil},
+		{"1,5", optional.Empty[float64](), strconv.ErrSyntax},
+		{"1e400", optional.Empty[float64](), strconv.ErrRange},
+	} {
+		c.check(t, try, lossy)
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	for _, c := range []parseCase[bool]{
+		{"true", optional.Of(true), nil},
+		{" F ", optional.Of(false), nil},
+		{"0", optional.Of(false), nil},
+		{"", optional.Empty[bool](), nil},
+		{"\t", optional.Empty[bool](), nil},
+		{"yes", optional.Empty[bool](), strconv.ErrSyntax},
+	} {
+		c.check(t, TryParseBool, ParseBool)
+	}
+}
+
+type celsius float32
+
+type port uint16
+
+func TestParseTo(t *testing.T) {
+	t.Run("int8", func(t *testing.T) {
+		for _, c := range []parseCase[int8]{
+			{"127", optional.Of(int8(127)), nil},
+			{"-128", optional.Of(int8(-128)), nil},
+			{"128", optional.Empty[int8](), strconv.ErrRange},
+			{"-129", optional.Empty[int8](), strconv.ErrRange},
+			{"0x10", optional.Empty[int8](), strconv.ErrSyntax},
+			{"", optional.Empty[int8](), nil},
+		} {
+			c.check(t, TryParseTo[int8], ParseTo[int8])
+		}
+	})
+	t.Run("uint8", func(t *testing.T) {
+		for _, c := range []parseCase[uint8]{
+			{"255", optional.Of(uint8(255)), nil},
+			{" 0 ", optional.Of(uint8(0)), nil},
+			{"256", optional.Empty[uint8](), strconv.ErrRange},
+			{"-1", optional.Empty[uint8](), strconv.ErrSyntax},
+			{" ", optional.Empty[uint8](), nil},
+		} {
+			c.check(t, TryParseTo[uint8], ParseTo[uint8])
+		}
+	})
+	t.Run("int", func(t *testing.T) {
+		for _, c := range []parseCase[int]{
+			{"-42", optional.Of(-42), nil},
+			{"1.5", optional.Empty[int](), strconv.ErrSyntax},
+		} {
+			c.check(t, TryParseTo[int], ParseTo[int])
+		}
+	})
+	t.Run("named uint16", func(t *testing.T) {
+		for _, c := range []parseCase[port]{
+			{"8080", optional.Of(port(8080)), nil},
+			{"65536", optional.Empty[port](), strconv.ErrRange},
+		} {
+			c.check(t, TryParseTo[port], ParseTo[port])
+		}
+	})
+	t.Run("named float32", func(t *testing.T) {
+		for _, c := range []parseCase[celsius]{
+			{"21.5", optional.Of(celsius(21.5)), nil},
+			{"1e39", optional.Empty[celsius](), strconv.ErrRange},
+			{"warm", optional.Empty[celsius](), strconv.ErrSyntax},
+		} {
+			c.check(t, TryParseTo[celsius], ParseTo[celsius])
+		}
+	})
+}
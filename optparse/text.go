@@ -0,0 +1,47 @@
+package optparse
+
+import (
+	"encoding"
+	"strings"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// TextUnmarshalerPtr is a constraint satisfied by *T when *T implements
+// encoding.TextUnmarshaler. It lets ParseText allocate a T and unmarshal into
+// it while the caller names only T.
+type TextUnmarshalerPtr[T any] interface {
+	*T
+	encoding.TextUnmarshaler
+}
+
+// ParseText parses s into a T using T's UnmarshalText method, returning an
+// empty Optional if s is blank or unmarshaling fails. It works with any type
+// whose pointer implements encoding.TextUnmarshaler, such as netip.Addr,
+// uuid.UUID or custom enums.
+//
+// Example:
+//
+//	addr := optparse.ParseText[netip.Addr](r.Header.Get("X-Real-IP"))
+func ParseText[T any, PT TextUnmarshalerPtr[T]](s string) optional.Optional[T] {
+	o, _ := TryParseText[T, PT](s)
+	return o
+}
+
+// TryParseText is like ParseText, but returns the error from UnmarshalText.
+// A blank s yields an empty Optional without calling UnmarshalText.
+//
+// Example:
+//
+//	level, err := optparse.TryParseText[slog.Level](os.Getenv("LOG_LEVEL"))
+func TryParseText[T any, PT TextUnmarshalerPtr[T]](s string) (optional.Optional[T], error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return optional.Empty[T](), nil
+	}
+	var val T
+	if err := PT(&val).UnmarshalText([]byte(s)); err != nil {
+		return optional.Empty[T](), err
+	}
+	return optional.Of(val), nil
+}
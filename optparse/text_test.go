@@ -0,0 +1,129 @@
+package optparse
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// uuid is a uuid.UUID look-alike: an array type with a pointer
+// UnmarshalText, so the zero value is meaningful on its own.
+type uuid [16]byte
+
+func (u *uuid) UnmarshalText(b []byte) error {
+	if len(b) != 36 || b[8] != '-' || b[13] != '-' || b[18] != '-' || b[23] != '-' {
+		return fmt.Errorf("invalid UUID %q", b)
+	}
+	var compact []byte
+	for i, c := range b {
+		if i != 8 && i != 13 && i != 18 && i != 23 {
+			compact = append(compact, c)
+		}
+	}
+	_, err := hex.Decode(u[:], compact)
+	return err
+}
+
+type color int
+
+const (
+	red color = iota + 1
+	green
+)
+
+var errUnknownColor = errors.New("unknown color")
+
+func (c *color) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "red":
+		*c = red
+	case "green":
+		*c = green
+	default:
+		return fmt.Errorf("%w %q", errUnknownColor, b)
+	}
+	return nil
+}
+
+// spy records the text it was asked to unmarshal.
+type spy struct {
+	calls int
+	text  string
+}
+
+func (s *spy) UnmarshalText(b []byte) error {
+	s.calls++
+	s.text = string(b)
+	return nil
+}
+
+type textCase[T any, PT TextUnmarshalerPtr[T]] struct {
+	in      string
+	want    optional.Optional[T]
+	wantErr bool
+}
+
+func (c textCase[T, PT]) run(t *testing.T) {
+	t.Helper()
+	got, err := TryParseText[T, PT](c.in)
+	if (err != nil) != c.wantErr {
+		t.Errorf("TryParseText[%T](%q) error = %v, wantErr %v", *new(T), c.in, err, c.wantErr)
+	}
+	if !reflect.DeepEqual(got, c.want) {
+		t.Errorf("TryParseText[%T](%q) = %v, want %v", *new(T), c.in, got, c.want)
+	}
+	if lossy := ParseText[T, PT](c.in); !reflect.DeepEqual(lossy, c.want) {
+		t.Errorf("ParseText[%T](%q) = %v, want %v", *new(T), c.in, lossy, c.want)
+	}
+}
+
+func TestParseText(t *testing.T) {
+	id := uuid{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	tests := []struct {
+		name string
+		run  func(*testing.T)
+	}{
+		{"uuid", textCase[uuid, *uuid]{in: "123e4567-e89b-12d3-a456-426614174000", want: optional.Of(id)}.run},
+		{"uuid nil", textCase[uuid, *uuid]{in: "00000000-0000-0000-0000-000000000000", want: optional.Of(uuid{})}.run},
+		{"uuid padded", textCase[uuid, *uuid]{in: "\t123e4567-e89b-12d3-a456-426614174000\n", want: optional.Of(id)}.run},
+		{"uuid malformed", textCase[uuid, *uuid]{in: "123e4567", wantErr: true}.run},
+		{"uuid bad hex", textCase[uuid, *uuid]{in: "123e4567-e89b-12d3-a456-42661417400g", wantErr: true}.run},
+		{"uuid blank", textCase[uuid, *uuid]{in: "   "}.run},
+		{"ipv4", textCase[netip.Addr, *netip.Addr]{in: "10.0.0.1", want: optional.Of(netip.MustParseAddr("10.0.0.1"))}.run},
+		{"ipv6", textCase[netip.Addr, *netip.Addr]{in: " ::1 ", want: optional.Of(netip.IPv6Loopback())}.run},
+		{"ip invalid", textCase[netip.Addr, *netip.Addr]{in: "10.0.0.256", wantErr: true}.run},
+		{"ip blank", textCase[netip.Addr, *netip.Addr]{in: ""}.run},
+		{"enum", textCase[color, *color]{in: "green", want: optional.Of(green)}.run},
+		{"enum unknown", textCase[color, *color]{in: "blue", wantErr: true}.run},
+		{"enum case", textCase[color, *color]{in: "Red", wantErr: true}.run},
+		{"enum blank", textCase[color, *color]{in: "\n"}.run},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
+
+func TestTryParseTextError(t *testing.T) {
+	_, err := TryParseText[color]("blue")
+	if !errors.Is(err, errUnknownColor) {
+		t.Errorf("TryParseText error = %v, want the UnmarshalText error", err)
+	}
+}
+
+func TestParseTextBlankSkipsUnmarshal(t *testing.T) {
+	for _, in := range []string{"", " ", "\t\n"} {
+		got, err := TryParseText[spy](in)
+		if got.IsPresent() || err != nil {
+			t.Errorf("TryParseText(%q) = %v, %v, want empty and no error", in, got, err)
+		}
+	}
+	got, _ := TryParseText[spy]("  value ")
+	if s, _ := got.Get(); s.calls != 1 || s.text != "value" {
+		t.Errorf("UnmarshalText called %d times with %q, want once with \"value\"", s.calls, s.text)
+	}
+}
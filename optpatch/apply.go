@@ -0,0 +1,282 @@
+// Package optpatch implements PATCH semantics for structs whose fields are
+// Optionals: each present field describes a change, each empty field means
+// "leave as is".
+//
+// Example usage:
+//
+//	type UserPatch struct {
+//	    Name  optional.Optional[string]
+//	    Email optional.Optional[string]
+//	    Age   optional.Optional[int] `patch:"Years"`
+//	}
+//
+//	err := optpatch.Apply(req, &user) // assigns only the present fields
+package optpatch
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// Apply assigns the value of every present Optional field in patch to the
+// corresponding field of the struct pointed to by target, leaving fields for
+// empty Optionals unchanged. patch may be a struct or a pointer to one.
+//
+// Fields are matched by name, or by the target field name given in the
+// `patch` struct tag; a tag of "-" skips the field. A target field may be of
+// the Optional's element type, of a named type with the same underlying type
+// (the value is converted), or an Optional of either (the value is wrapped).
+// Plain struct fields in patch are applied recursively to the matching target
+// struct or pointer to struct, allocating nil pointers as needed, including
+// embedded pointers that a promoted target field is reached through. Like an
+// Optional field, a plain struct field with no matching target field, or one
+// whose target is not a struct, is an error. Patch fields that are neither
+// Optionals nor structs are ignored.
+//
+// Apply validates the whole patch before assigning anything, so on error
+// target is left unchanged. Errors name the offending field.
+func Apply(patch any, target any) error {
+	ops, err := plan(patch, target)
+	if err != nil {
+		return err
+	}
+	for _, op := range ops {
+		op.apply()
+	}
+	return nil
+}
+
+// DryRun reports which target fields Apply would change, without modifying
+// target. Fields are reported as dotted paths of target field names, in
+// declaration order. A present patch value equal to the current target value
+// (according to reflect.DeepEqual) is not reported.
+//
+// Example:
+//
+//	changed, err := optpatch.DryRun(req, &user) // e.g. ["Name", "Address.City"]
+func DryRun(patch any, target any) ([]string, error) {
+	ops, err := plan(patch, target)
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	for _, op := range ops {
+		if op.changes() {
+			changed = append(changed, op.path)
+		}
+	}
+	return changed, nil
+}
+
+// assignment is a single field update planned by Apply.
+type assignment struct {
+	path string
+	// resolve returns the target field, allocating intermediate nil pointers
+	// if alloc is true. It returns an invalid value if alloc is false and an
+	// intermediate pointer is nil.
+	resolve func(alloc bool) reflect.Value
+	value   reflect.Value
+}
+
+func (a assignment) apply() {
+	a.resolve(true).Set(a.value)
+}
+
+func (a assignment) changes() bool {
+	current := a.resolve(false)
+	if !current.IsValid() {
+		return true
+	}
+	return !reflect.DeepEqual(current.Interface(), a.value.Interface())
+}
+
+func plan(patch any, target any) ([]assignment, error) {
+	pv := reflect.ValueOf(patch)
+	if pv.Kind() == reflect.Ptr {
+		if pv.IsNil() {
+			return nil, errors.New("optpatch: patch is a nil pointer")
+		}
+		pv = pv.Elem()
+	}
+	if pv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("optpatch: patch must be a struct, got %T", patch)
+	}
+	tv := reflect.ValueOf(target)
+	if tv.Kind() != reflect.Ptr || tv.IsNil() || tv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("optpatch: target must be a non-nil pointer to a struct, got %T", target)
+	}
+	root := tv.Elem()
+	return planStruct(pv, root.Type(), func(bool) reflect.Value { return root }, "", nil)
+}
+
+func planStruct(pv reflect.Value, tt reflect.Type, resolve func(bool) reflect.Value, prefix string, ops []assignment) ([]assignment, error) {
+	pt := pv.Type()
+	for i := 0; i < pt.NumField(); i++ {
+		field := pt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Tag.Get("patch")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		path := prefix + name
+		fv := pv.Field(i)
+
+		_, isOptional := optional.ElemType(field.Type)
+		if !isOptional && field.Type.Kind() != reflect.Struct {
+			continue
+		}
+		targetField, ok := tt.FieldByName(name)
+		if !ok || targetField.PkgPath != "" {
+			return nil, fmt.Errorf("optpatch: field %s: no exported field %s in %s", field.Name, path, tt)
+		}
+		fieldResolve := fieldResolver(resolve, targetField.Index)
+		reachable := func() error {
+			if cannotAllocate(resolve(false), tt, targetField.Index) {
+				return fmt.Errorf("optpatch: field %s: cannot allocate nil embedded pointer to unexported struct in %s", path, tt)
+			}
+			return nil
+		}
+
+		if !isOptional {
+			nestedType := targetField.Type
+			nestedResolve := fieldResolve
+			if nestedType.Kind() == reflect.Ptr {
+				nestedType = nestedType.Elem()
+				nestedResolve = pointerResolver(fieldResolve)
+			}
+			if nestedType.Kind() != reflect.Struct {
+				return nil, fmt.Errorf("optpatch: field %s: cannot apply struct %s to %s", path, field.Type, targetField.Type)
+			}
+			planned := len(ops)
+			var err error
+			ops, err = planStruct(fv, nestedType, nestedResolve, path+".", ops)
+			if err != nil {
+				return nil, err
+			}
+			if len(ops) > planned {
+				if err := reachable(); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		val, present := optional.ReflectGet(fv)
+		if !present {
+			continue
+		}
+		if err := reachable(); err != nil {
+			return nil, err
+		}
+		converted, err := convert(val, targetField.Type)
+		if err != nil {
+			return nil, fmt.Errorf("optpatch: field %s: %w", path, err)
+		}
+		ops = append(ops, assignment{path: path, resolve: fieldResolve, value: converted})
+	}
+	return ops, nil
+}
+
+// convert returns val as a value of type to, wrapping it in an Optional if to
+// is an Optional type.
+func convert(val reflect.Value, to reflect.Type) (reflect.Value, error) {
+	if elem, ok := optional.ElemType(to); ok {
+		inner, err := convert(val, elem)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return optional.ReflectOf(to, inner), nil
+	}
+	from := val.Type()
+	switch {
+	case from.AssignableTo(to):
+		return val, nil
+	case from.Kind() == to.Kind() && from.ConvertibleTo(to):
+		return val.Convert(to), nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot assign %s to %s", from, to)
+}
+
+func fieldResolver(parent func(bool) reflect.Value, index []int) func(bool) reflect.Value {
+	return func(alloc bool) reflect.Value {
+		v := parent(alloc)
+		if !v.IsValid() {
+			return v
+		}
+		if alloc {
+			return fieldByIndexAlloc(v, index)
+		}
+		f, err := v.FieldByIndexErr(index)
+		if err != nil {
+			return reflect.Value{}
+		}
+		return f
+	}
+}
+
+// fieldByIndexAlloc is like reflect.Value.FieldByIndex, but allocates nil
+// embedded pointers that a promoted field is reached through.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// cannotAllocate reports whether reaching the field of t at index from v, a
+// struct value of type t or an invalid value if Apply will allocate it,
+// requires allocating a nil embedded pointer to an unexported struct type,
+// which reflection cannot do.
+func cannotAllocate(v reflect.Value, t reflect.Type, index []int) bool {
+	for _, x := range index[:len(index)-1] {
+		f := t.Field(x)
+		if v.IsValid() {
+			v = v.Field(x)
+		}
+		t = f.Type
+		if t.Kind() != reflect.Ptr {
+			continue
+		}
+		if v.IsValid() && v.IsNil() {
+			v = reflect.Value{}
+		}
+		if !v.IsValid() && f.PkgPath != "" {
+			return true
+		}
+		if v.IsValid() {
+			v = v.Elem()
+		}
+		t = t.Elem()
+	}
+	return false
+}
+
+func pointerResolver(parent func(bool) reflect.Value) func(bool) reflect.Value {
+	return func(alloc bool) reflect.Value {
+		v := parent(alloc)
+		if !v.IsValid() {
+			return v
+		}
+		if v.IsNil() {
+			if !alloc {
+				return reflect.Value{}
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return v.Elem()
+	}
+}
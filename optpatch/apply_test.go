@@ -0,0 +1,200 @@
+package optpatch
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+type Audit struct {
+	UpdatedBy string
+}
+
+type audit struct {
+	Reason string
+}
+
+type Record struct {
+	*Audit
+	*audit
+	Name string
+}
+
+type RecordPatch struct {
+	UpdatedBy optional.Optional[string]
+	Reason    optional.Optional[string]
+	Name      optional.Optional[string]
+}
+
+func TestApplyThroughNilEmbeddedPointer(t *testing.T) {
+	var r Record
+	err := Apply(RecordPatch{UpdatedBy: optional.Of("ada")}, &r)
+	if err != nil {
+		t.Fatalf("Apply = %v", err)
+	}
+	if r.Audit == nil || r.UpdatedBy != "ada" {
+		t.Errorf("Apply left %+v, want Audit allocated with UpdatedBy ada", r)
+	}
+}
+
+func TestApplyThroughUnexportedEmbeddedPointer(t *testing.T) {
+	var r Record
+	err := Apply(RecordPatch{Reason: optional.Of("fix"), Name: optional.Of("x")}, &r)
+	if err == nil || !strings.Contains(err.Error(), "field Reason") {
+		t.Fatalf("Apply = %v, want an error naming Reason", err)
+	}
+	if r.Name != "" {
+		t.Errorf("failed Apply modified the target: %+v", r)
+	}
+
+	r.audit = &audit{}
+	if err := Apply(RecordPatch{Reason: optional.Of("fix")}, &r); err != nil {
+		t.Fatalf("Apply with allocated pointer = %v", err)
+	}
+	if r.Reason != "fix" {
+		t.Errorf("Reason = %q, want fix", r.Reason)
+	}
+}
+
+func TestDryRunThroughNilEmbeddedPointer(t *testing.T) {
+	var r Record
+	changed, err := DryRun(RecordPatch{UpdatedBy: optional.Of("ada"), Name: optional.Of("")}, &r)
+	if err != nil {
+		t.Fatalf("DryRun = %v", err)
+	}
+	if want := []string{"UpdatedBy"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("DryRun = %v, want %v", changed, want)
+	}
+	if r.Audit != nil {
+		t.Error("DryRun allocated the embedded pointer")
+	}
+}
+
+func TestDiffThroughNilEmbeddedPointer(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after Record
+		want          RecordPatch
+	}{
+		{"both nil", Record{}, Record{}, RecordPatch{}},
+		{"set", Record{}, Record{Audit: &Audit{UpdatedBy: "ada"}}, RecordPatch{UpdatedBy: optional.Of("ada")}},
+		{"cleared", Record{Audit: &Audit{UpdatedBy: "ada"}}, Record{}, RecordPatch{UpdatedBy: optional.Of("")}},
+		{"nil vs zero", Record{}, Record{Audit: &Audit{}}, RecordPatch{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff[RecordPatch](tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("Diff = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	type Address struct {
+		City string
+	}
+	type Level int
+	type User struct {
+		Name    string
+		Years   int
+		Level   Level
+		Email   optional.Optional[string]
+		Address *Address
+	}
+	type AddressPatch struct {
+		City optional.Optional[string]
+	}
+	type UserPatch struct {
+		Name    optional.Optional[string]
+		Age     optional.Optional[int] `patch:"Years"`
+		Level   optional.Optional[int]
+		Email   optional.Optional[string]
+		Address AddressPatch
+		Ignored optional.Optional[string] `patch:"-"`
+	}
+
+	u := User{Name: "old", Years: 30}
+	patch := UserPatch{
+		Age:     optional.Of(31),
+		Level:   optional.Of(2),
+		Email:   optional.Of("a@example.com"),
+		Address: AddressPatch{City: optional.Of("Paris")},
+		Ignored: optional.Of("x"),
+	}
+	changed, err := DryRun(patch, &u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Years", "Level", "Email", "Address.City"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("DryRun = %v, want %v", changed, want)
+	}
+	if err := Apply(&patch, &u); err != nil {
+		t.Fatal(err)
+	}
+	want := User{Name: "old", Years: 31, Level: 2, Email: optional.Of("a@example.com"), Address: &Address{City: "Paris"}}
+	if !reflect.DeepEqual(u, want) {
+		t.Errorf("Apply = %+v, want %+v", u, want)
+	}
+
+	type BadPatch struct {
+		Name optional.Optional[int]
+	}
+	if err := Apply(BadPatch{Name: optional.Of(1)}, &u); err == nil || !strings.Contains(err.Error(), "field Name") {
+		t.Errorf("Apply with mismatched type = %v, want an error naming Name", err)
+	}
+}
+
+func TestApplyNonOptionalFields(t *testing.T) {
+	type Address struct {
+		City string
+	}
+	type User struct {
+		Name    string
+		Address Address
+		Email   string
+	}
+	type AddressPatch struct {
+		City optional.Optional[string]
+	}
+
+	type PlainPatch struct {
+		Name    optional.Optional[string]
+		Note    string
+		Count   int
+		Missing []string
+	}
+	u := User{Name: "old"}
+	if err := Apply(PlainPatch{Name: optional.Of("new"), Note: "n", Count: 1, Missing: []string{"x"}}, &u); err != nil {
+		t.Fatalf("Apply with plain non-struct fields = %v, want them ignored", err)
+	}
+	if u.Name != "new" {
+		t.Errorf("Name = %q, want new", u.Name)
+	}
+
+	type UnmatchedPatch struct {
+		Name optional.Optional[string]
+		Home AddressPatch
+	}
+	err := Apply(UnmatchedPatch{Name: optional.Of("x")}, &u)
+	if err == nil || !strings.Contains(err.Error(), "field Home: no exported field Home") {
+		t.Errorf("Apply with an unmatched struct field = %v, want an error naming Home", err)
+	}
+
+	type MistypedPatch struct {
+		Email AddressPatch
+	}
+	err = Apply(MistypedPatch{}, &u)
+	if err == nil || !strings.Contains(err.Error(), "field Email: cannot apply struct") {
+		t.Errorf("Apply with a struct field targeting a string = %v, want an error naming Email", err)
+	}
+	if u.Name != "new" || u.Email != "" {
+		t.Errorf("failed Apply modified the target: %+v", u)
+	}
+}
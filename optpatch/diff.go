@@ -0,0 +1,160 @@
+package optpatch
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// Diff compares two snapshots of a struct and returns a patch struct of type P
+// describing the changes. It is the inverse of Apply: applying the result to
+// before yields after for every field P mirrors. See DiffInto for the rules.
+//
+// Example:
+//
+//	patch, err := optpatch.Diff[UserPatch](oldUser, newUser)
+//	auditLog.Record(patch)
+func Diff[P any, T any](before, after T) (P, error) {
+	var patch P
+	err := DiffInto(before, after, &patch)
+	return patch, err
+}
+
+// DiffInto compares before and after, which must be structs of the same type
+// or pointers to them, and fills the struct pointed to by patchDest: each
+// Optional field is present with the value from after if the corresponding
+// field differs, and empty otherwise. Every Optional field of patchDest is
+// overwritten, so a patch can be reused.
+//
+// Fields are matched by name, or by the `patch` struct tag, exactly as in
+// Apply; a tag of "-" skips the field. Values are converted to the Optional's
+// element type under the same rules. Comparable types are compared with ==
+// and all others with reflect.DeepEqual. A field promoted through a nil
+// embedded pointer compares as the zero value of its type.
+//
+// The shape of patchDest decides how nested structs are compared: an Optional
+// of a struct type treats the nested struct atomically, while a plain struct
+// field is diffed recursively into its own Optional fields.
+func DiffInto(before, after any, patchDest any) error {
+	bv, err := structValue(before, "before")
+	if err != nil {
+		return err
+	}
+	av, err := structValue(after, "after")
+	if err != nil {
+		return err
+	}
+	if bv.Type() != av.Type() {
+		return fmt.Errorf("optpatch: before and after have different types %s and %s", bv.Type(), av.Type())
+	}
+	pv := reflect.ValueOf(patchDest)
+	if pv.Kind() != reflect.Ptr || pv.IsNil() || pv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("optpatch: patchDest must be a non-nil pointer to a struct, got %T", patchDest)
+	}
+	return diffStruct(bv, av, pv.Elem(), "")
+}
+
+func diffStruct(bv, av, pv reflect.Value, prefix string) error {
+	pt := pv.Type()
+	for i := 0; i < pt.NumField(); i++ {
+		field := pt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Tag.Get("patch")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		path := prefix + name
+
+		elem, isOptional := optional.ElemType(field.Type)
+		if !isOptional && field.Type.Kind() != reflect.Struct {
+			continue
+		}
+		srcField, ok := bv.Type().FieldByName(name)
+		if !ok || srcField.PkgPath != "" {
+			return fmt.Errorf("optpatch: field %s: no exported field %s in %s", field.Name, path, bv.Type())
+		}
+		before := fieldOrZero(bv, srcField)
+		after := fieldOrZero(av, srcField)
+
+		if !isOptional {
+			if srcField.Type.Kind() != reflect.Struct {
+				return fmt.Errorf("optpatch: field %s: cannot diff %s into struct %s", path, srcField.Type, field.Type)
+			}
+			if err := diffStruct(before, after, pv.Field(i), path+"."); err != nil {
+				return err
+			}
+			continue
+		}
+
+		converted, err := convert(after, elem)
+		if err != nil {
+			return fmt.Errorf("optpatch: field %s: %w", path, err)
+		}
+		if equal(before, after) {
+			pv.Field(i).Set(reflect.Zero(field.Type))
+		} else {
+			pv.Field(i).Set(optional.ReflectOf(field.Type, converted))
+		}
+	}
+	return nil
+}
+
+// fieldOrZero returns the field of v described by f, or the zero value of its
+// type if it is promoted through a nil embedded pointer.
+func fieldOrZero(v reflect.Value, f reflect.StructField) reflect.Value {
+	fv, err := v.FieldByIndexErr(f.Index)
+	if err != nil {
+		return reflect.Zero(f.Type)
+	}
+	return fv
+}
+
+// equal compares a and b with == when their type is comparable, falling back
+// to reflect.DeepEqual. Interface types are always compared deeply because
+// their dynamic values may not be comparable.
+func equal(a, b reflect.Value) bool {
+	t := a.Type()
+	if t.Comparable() && !containsInterface(t) {
+		return a.Interface() == b.Interface()
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// containsInterface reports whether comparing values of the comparable type t
+// with == could panic because t contains an interface.
+func containsInterface(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Array:
+		return containsInterface(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if containsInterface(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func structValue(v any, name string) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}, errors.New("optpatch: " + name + " is a nil pointer")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("optpatch: %s must be a struct, got %T", name, v)
+	}
+	return rv, nil
+}
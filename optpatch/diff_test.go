@@ -0,0 +1,190 @@
+package optpatch
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+type Tier int
+
+type Contact struct {
+	Email string
+	Phone string
+}
+
+type Account struct {
+	Name  string
+	Tier  Tier
+	Tags  []string
+	Meta  map[string]string
+	Extra any
+	Pair  [2]any
+	Home  Contact
+	Work  Contact
+	Plain int
+}
+
+type ContactPatch struct {
+	Email optional.Optional[string]
+	Phone optional.Optional[string]
+}
+
+type AccountPatch struct {
+	Name    optional.Optional[string]
+	Level   optional.Optional[int] `patch:"Tier"`
+	Tags    optional.Optional[[]string]
+	Meta    optional.Optional[map[string]string]
+	Extra   optional.Optional[any]
+	Pair    optional.Optional[[2]any]
+	Home    ContactPatch
+	Work    optional.Optional[Contact]
+	Ignored optional.Optional[int] `patch:"-"`
+	Note    string
+}
+
+func TestDiff(t *testing.T) {
+	base := Account{
+		Name:  "ada",
+		Tier:  1,
+		Tags:  []string{"a"},
+		Meta:  map[string]string{"k": "v"},
+		Extra: []int{1},
+		Pair:  [2]any{[]int{1}, "x"},
+		Home:  Contact{Email: "a@home", Phone: "1"},
+		Work:  Contact{Email: "a@work", Phone: "2"},
+	}
+	with := func(fn func(*Account)) Account {
+		a := base
+		a.Tags = append([]string(nil), base.Tags...)
+		a.Meta = map[string]string{"k": "v"}
+		a.Extra = []int{1}
+		a.Pair = [2]any{[]int{1}, "x"}
+		fn(&a)
+		return a
+	}
+	tests := []struct {
+		name  string
+		after Account
+		want  AccountPatch
+	}{
+		{"unchanged deep copies", with(func(*Account) {}), AccountPatch{}},
+		{"scalar", with(func(a *Account) { a.Name = "bob" }), AccountPatch{Name: optional.Of("bob")}},
+		{"converted", with(func(a *Account) { a.Tier = 3 }), AccountPatch{Level: optional.Of(3)}},
+		{"slice", with(func(a *Account) { a.Tags = append(a.Tags, "b") }), AccountPatch{Tags: optional.Of([]string{"a", "b"})}},
+		{"slice to nil", with(func(a *Account) { a.Tags = nil }), AccountPatch{Tags: optional.Of([]string(nil))}},
+		{"map", with(func(a *Account) { a.Meta["k"] = "w" }), AccountPatch{Meta: optional.Of(map[string]string{"k": "w"})}},
+		{"interface", with(func(a *Account) { a.Extra = []int{2} }), AccountPatch{Extra: optional.Of[any]([]int{2})}},
+		{"interface dynamic type", with(func(a *Account) { a.Extra = 1 }), AccountPatch{Extra: optional.Of[any](1)}},
+		{"array of interfaces", with(func(a *Account) { a.Pair[0] = []int{2} }), AccountPatch{Pair: optional.Of([2]any{[]int{2}, "x"})}},
+		{"nested recursive", with(func(a *Account) { a.Home.Phone = "9" }), AccountPatch{Home: ContactPatch{Phone: optional.Of("9")}}},
+		{"nested atomic", with(func(a *Account) { a.Work.Phone = "9" }), AccountPatch{Work: optional.Of(Contact{Email: "a@work", Phone: "9"})}},
+		{"unmirrored field", with(func(a *Account) { a.Plain = 5 }), AccountPatch{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff[AccountPatch](base, tt.after)
+			if err != nil {
+				t.Fatalf("Diff = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff =\n%+v\nwant\n%+v", got, tt.want)
+			}
+
+			// Applying the patch to before must give after, for the fields the
+			// patch mirrors.
+			applied := with(func(*Account) {})
+			if err := Apply(got, &applied); err != nil {
+				t.Fatalf("Apply(Diff) = %v", err)
+			}
+			applied.Plain = tt.after.Plain
+			if !reflect.DeepEqual(applied, tt.after) {
+				t.Errorf("Apply(Diff) =\n%+v\nwant\n%+v", applied, tt.after)
+			}
+		})
+	}
+}
+
+func TestDiffIntoReusesPatch(t *testing.T) {
+	patch := AccountPatch{
+		Name:    optional.Of("stale"),
+		Home:    ContactPatch{Email: optional.Of("stale")},
+		Ignored: optional.Of(1),
+		Note:    "kept",
+	}
+	a := Account{Name: "ada", Tier: 1}
+	b := a
+	b.Tier = 2
+	if err := DiffInto(&a, &b, &patch); err != nil {
+		t.Fatal(err)
+	}
+	want := AccountPatch{Level: optional.Of(2), Ignored: optional.Of(1), Note: "kept"}
+	if !reflect.DeepEqual(patch, want) {
+		t.Errorf("DiffInto = %+v, want %+v", patch, want)
+	}
+}
+
+func TestContainsInterface(t *testing.T) {
+	type withAny struct {
+		A int
+		B struct{ C error }
+	}
+	tests := []struct {
+		v    any
+		want bool
+	}{
+		{0, false},
+		{"", false},
+		{[2]int{}, false},
+		{Contact{}, false},
+		{new(any), false},
+		{[2]any{}, true},
+		{[1][1]error{}, true},
+		{withAny{}, true},
+		{struct{ P *any }{}, false},
+	}
+	for _, tt := range tests {
+		typ := reflect.TypeOf(tt.v)
+		if got := containsInterface(typ); got != tt.want {
+			t.Errorf("containsInterface(%s) = %v, want %v", typ, got, tt.want)
+		}
+	}
+	if !containsInterface(reflect.TypeOf((*any)(nil)).Elem()) {
+		t.Error("containsInterface(interface {}) = false")
+	}
+}
+
+func TestDiffErrors(t *testing.T) {
+	var nilAccount *Account
+	a := Account{}
+	tests := []struct {
+		name   string
+		before any
+		after  any
+		dest   any
+		want   string
+	}{
+		{"before not struct", 1, a, &AccountPatch{}, "optpatch: before must be a struct, got int"},
+		{"after not struct", a, "x", &AccountPatch{}, "optpatch: after must be a struct, got string"},
+		{"before nil pointer", nilAccount, a, &AccountPatch{}, "optpatch: before is a nil pointer"},
+		{"after nil pointer", &a, nilAccount, &AccountPatch{}, "optpatch: after is a nil pointer"},
+		{"before pointer to non-struct", new(int), a, &AccountPatch{}, "optpatch: before must be a struct, got *int"},
+		{"different types", a, Contact{}, &AccountPatch{}, "optpatch: before and after have different types optpatch.Account and optpatch.Contact"},
+		{"dest not pointer", a, a, AccountPatch{}, "optpatch: patchDest must be a non-nil pointer to a struct, got optpatch.AccountPatch"},
+		{"dest nil", a, a, (*AccountPatch)(nil), "optpatch: patchDest must be a non-nil pointer to a struct, got *optpatch.AccountPatch"},
+		{"missing field", a, a, &struct{ Age optional.Optional[int] }{}, "optpatch: field Age: no exported field Age in optpatch.Account"},
+		{"struct into scalar", a, a, &struct{ Name ContactPatch }{}, "optpatch: field Name: cannot diff string into struct optpatch.ContactPatch"},
+		{"unconvertible", a, a, &struct{ Name optional.Optional[int] }{}, "optpatch: field Name: cannot assign string to int"},
+		{"nested missing field", a, a, &struct{ Home struct{ Fax optional.Optional[string] } }{}, "optpatch: field Fax: no exported field Home.Fax in optpatch.Contact"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DiffInto(tt.before, tt.after, tt.dest)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("DiffInto = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
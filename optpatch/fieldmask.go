@@ -0,0 +1,164 @@
+package optpatch
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// NamingStrategy returns the field mask path segment for a struct field. An
+// empty result falls back to SnakeCase, and "-" skips the field.
+type NamingStrategy func(field reflect.StructField) string
+
+// SnakeCase names fields by converting their Go name to snake_case, so
+// DisplayName becomes display_name and UserID becomes user_id.
+func SnakeCase(field reflect.StructField) string {
+	return toSnakeCase(field.Name)
+}
+
+// JSONTag names fields by the name in their `json` struct tag.
+func JSONTag(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return name
+}
+
+// ProtobufTag names fields by the name= option of the `protobuf` struct tag
+// emitted by protoc-gen-go, which is the field name used in proto field masks.
+func ProtobufTag(field reflect.StructField) string {
+	for _, part := range strings.Split(field.Tag.Get("protobuf"), ",") {
+		if strings.HasPrefix(part, "name=") {
+			return strings.TrimPrefix(part, "name=")
+		}
+	}
+	return ""
+}
+
+// TagName returns a NamingStrategy that names fields by the given struct tag.
+//
+// Example:
+//
+//	paths, err := optpatch.FieldMask(req, optpatch.WithNaming(optpatch.TagName("mask")))
+func TagName(tag string) NamingStrategy {
+	return func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		return name
+	}
+}
+
+// MaskOption configures FieldMask.
+type MaskOption func(*maskConfig)
+
+type maskConfig struct {
+	naming NamingStrategy
+	sorted bool
+}
+
+// WithNaming sets the strategy used to name path segments. The default is SnakeCase.
+func WithNaming(naming NamingStrategy) MaskOption {
+	return func(c *maskConfig) {
+		c.naming = naming
+	}
+}
+
+// Sorted makes FieldMask return paths in lexical order instead of field
+// declaration order.
+func Sorted() MaskOption {
+	return func(c *maskConfig) {
+		c.sorted = true
+	}
+}
+
+// FieldMask returns the paths of all present Optional fields in patch, suitable
+// for the update_mask of a Google-style update request. patch may be a struct
+// or a pointer to one.
+//
+// Plain struct fields are recursed into with dotted paths, and embedded structs
+// are flattened into their parent. Paths appear in field declaration order
+// unless Sorted is given, so the same patch struct used with Apply yields a
+// deterministic mask.
+//
+// Example:
+//
+//	paths, err := optpatch.FieldMask(req) // e.g. ["display_name", "address.city"]
+func FieldMask(patch any, opts ...MaskOption) ([]string, error) {
+	cfg := maskConfig{naming: SnakeCase}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	pv := reflect.ValueOf(patch)
+	if pv.Kind() == reflect.Ptr {
+		if pv.IsNil() {
+			return nil, errors.New("optpatch: patch is a nil pointer")
+		}
+		pv = pv.Elem()
+	}
+	if pv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("optpatch: patch must be a struct, got %T", patch)
+	}
+	paths := collectPaths(pv, cfg.naming, "", nil)
+	if cfg.sorted {
+		sort.Strings(paths)
+	}
+	return paths, nil
+}
+
+func collectPaths(v reflect.Value, naming NamingStrategy, prefix string, paths []string) []string {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if _, isOptional := optional.ElemType(field.Type); !isOptional {
+				paths = collectPaths(v.Field(i), naming, prefix, paths)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		name := naming(field)
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = toSnakeCase(field.Name)
+		}
+		fv := v.Field(i)
+		if _, isOptional := optional.ElemType(field.Type); isOptional {
+			if _, present := optional.ReflectGet(fv); present {
+				paths = append(paths, prefix+name)
+			}
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct {
+			paths = collectPaths(fv, naming, prefix+name+".", paths)
+		}
+	}
+	return paths
+}
+
+// toSnakeCase converts a Go identifier to snake_case, keeping initialisms
+// together: "HTTPServerURL" becomes "http_server_url".
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				prev := runes[i-1]
+				nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+					b.WriteByte('_')
+				}
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,167 @@
+package optpatch
+
+import (
+	"reflect"
+	"testing"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+type GeoMask struct {
+	Lat optional.Optional[float64] `json:"lat" protobuf:"fixed64,1,opt,name=lat,proto3" mask:"latitude"`
+	Lng optional.Optional[float64] `json:"lng" protobuf:"fixed64,2,opt,name=lng,proto3" mask:"longitude"`
+}
+
+type PlaceMask struct {
+	City optional.Optional[string] `json:"city,omitempty" protobuf:"bytes,1,opt,name=city,proto3" mask:"town"`
+	Geo  GeoMask                   `json:"geo" protobuf:"bytes,2,opt,name=geo,proto3" mask:"coords"`
+}
+
+type AuditMask struct {
+	UpdatedBy optional.Optional[string] `json:"updatedBy" protobuf:"bytes,9,opt,name=updated_by,json=updatedBy,proto3" mask:"by"`
+}
+
+type ProfileMask struct {
+	AuditMask
+	DisplayName optional.Optional[string] `json:"displayName" protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" mask:"name"`
+	UserID      optional.Optional[int64]  `json:"userId" protobuf:"varint,2,opt,name=user_id,json=userId,proto3"`
+	HTTPServer  optional.Optional[string] `json:"-" protobuf:"bytes,3,opt,name=http_server,proto3" mask:"-"`
+	Home        PlaceMask                 `json:"home" protobuf:"bytes,4,opt,name=home,proto3" mask:"home"`
+	Tags        optional.Optional[[]string]
+	Plain       string `json:"plain"`
+	secret      optional.Optional[string]
+}
+
+func fullProfile() ProfileMask {
+	return ProfileMask{
+		AuditMask:   AuditMask{UpdatedBy: optional.Of("ada")},
+		DisplayName: optional.Of("Ada"),
+		UserID:      optional.Of(int64(7)),
+		HTTPServer:  optional.Of("web-1"),
+		Home: PlaceMask{
+			City: optional.Of("London"),
+			Geo:  GeoMask{Lat: optional.Of(51.5), Lng: optional.Of(-0.1)},
+		},
+		Tags:   optional.Of([]string{"a"}),
+		Plain:  "x",
+		secret: optional.Of("s"),
+	}
+}
+
+func TestFieldMask(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch ProfileMask
+		opts  []MaskOption
+		want  []string
+	}{
+		{
+			name:  "snake case",
+			patch: fullProfile(),
+			want:  []string{"updated_by", "display_name", "user_id", "http_server", "home.city", "home.geo.lat", "home.geo.lng", "tags"},
+		},
+		{
+			name:  "json tag",
+			patch: fullProfile(),
+			opts:  []MaskOption{WithNaming(JSONTag)},
+			want:  []string{"updatedBy", "displayName", "userId", "home.city", "home.geo.lat", "home.geo.lng", "tags"},
+		},
+		{
+			name:  "protobuf tag",
+			patch: fullProfile(),
+			opts:  []MaskOption{WithNaming(ProtobufTag)},
+			want:  []string{"updated_by", "display_name", "user_id", "http_server", "home.city", "home.geo.lat", "home.geo.lng", "tags"},
+		},
+		{
+			name:  "custom tag",
+			patch: fullProfile(),
+			opts:  []MaskOption{WithNaming(TagName("mask"))},
+			want:  []string{"by", "name", "user_id", "home.town", "home.coords.latitude", "home.coords.longitude", "tags"},
+		},
+		{
+			name:  "sorted",
+			patch: fullProfile(),
+			opts:  []MaskOption{Sorted()},
+			want:  []string{"display_name", "home.city", "home.geo.lat", "home.geo.lng", "http_server", "tags", "updated_by", "user_id"},
+		},
+		{
+			name:  "sorted custom tag",
+			patch: fullProfile(),
+			opts:  []MaskOption{WithNaming(TagName("mask")), Sorted()},
+			want:  []string{"by", "home.coords.latitude", "home.coords.longitude", "home.town", "name", "tags", "user_id"},
+		},
+		{
+			name:  "nested only",
+			patch: ProfileMask{Home: PlaceMask{Geo: GeoMask{Lng: optional.Of(0.0)}}},
+			want:  []string{"home.geo.lng"},
+		},
+		{
+			name:  "present zero values",
+			patch: ProfileMask{DisplayName: optional.Of(""), Tags: optional.Of([]string(nil))},
+			want:  []string{"display_name", "tags"},
+		},
+		{
+			name:  "nothing present",
+			patch: ProfileMask{Plain: "x"},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FieldMask(tt.patch, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FieldMask = %q, want %q", got, tt.want)
+			}
+			ptr, err := FieldMask(&tt.patch, tt.opts...)
+			if err != nil || !reflect.DeepEqual(ptr, got) {
+				t.Errorf("FieldMask of a pointer = %q, %v, want %q", ptr, err, got)
+			}
+		})
+	}
+}
+
+func TestFieldMaskErrors(t *testing.T) {
+	var nilPatch *ProfileMask
+	tests := []struct {
+		patch any
+		want  string
+	}{
+		{nilPatch, "optpatch: patch is a nil pointer"},
+		{42, "optpatch: patch must be a struct, got int"},
+		{new(map[string]string), "optpatch: patch must be a struct, got *map[string]string"},
+		{nil, "optpatch: patch must be a struct, got <nil>"},
+	}
+	for _, tt := range tests {
+		paths, err := FieldMask(tt.patch)
+		if err == nil || err.Error() != tt.want || paths != nil {
+			t.Errorf("FieldMask(%T) = %q, %v, want error %q", tt.patch, paths, err, tt.want)
+		}
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Name", "name"},
+		{"DisplayName", "display_name"},
+		{"UserID", "user_id"},
+		{"ID", "id"},
+		{"HTTPServer", "http_server"},
+		{"HTTPServerURL", "http_server_url"},
+		{"ServeHTTP", "serve_http"},
+		{"Level2Cache", "level2_cache"},
+		{"UserID2", "user_id2"},
+		{"already_snake", "already_snake"},
+		{"Ünïcode", "ünïcode"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := toSnakeCase(tt.in); got != tt.want {
+			t.Errorf("toSnakeCase(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package optpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// MergePatch encodes patch as a minimal RFC 7386 JSON merge patch. Every
+// present Optional field becomes a key and every empty one is omitted, so an
+// absent field and a field present with its zero value are always
+// distinguishable. patch may be a struct or a pointer to one.
+//
+// Keys are taken from `json` struct tags, falling back to the field name, and
+// a tag of "-" skips the field. Plain struct fields are encoded as nested patch
+// objects and omitted when they contain no present fields; embedded structs
+// are flattened into their parent. Other non-Optional fields are ignored.
+// Present values are encoded with encoding/json, and keys appear in field
+// declaration order.
+//
+// To clear a field, the merge patch must contain an explicit null. Use an
+// Optional of a pointer, map, slice or interface type and make it present
+// with a nil value:
+//
+//	type UserPatch struct {
+//	    Name     optional.Optional[string]  `json:"name"`
+//	    Nickname optional.Optional[*string] `json:"nickname"`
+//	}
+//
+//	data, _ := optpatch.MergePatch(UserPatch{
+//	    Name:     optional.Of(""),
+//	    Nickname: optional.Of[*string](nil),
+//	}) // {"name":"","nickname":null}
+func MergePatch(patch any) ([]byte, error) {
+	pv := reflect.ValueOf(patch)
+	if pv.Kind() == reflect.Ptr {
+		if pv.IsNil() {
+			return nil, errors.New("optpatch: patch is a nil pointer")
+		}
+		pv = pv.Elem()
+	}
+	if pv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("optpatch: patch must be a struct, got %T", patch)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	if _, err := writeMergeFields(&buf, pv, false); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// writeMergeFields writes the members of the merge patch object for v,
+// separated by commas, and reports whether any member was written. wrote
+// indicates whether the enclosing object already has members.
+func writeMergeFields(buf *bytes.Buffer, v reflect.Value, wrote bool) (bool, error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, skip := jsonName(field)
+		if skip {
+			continue
+		}
+		fv := v.Field(i)
+
+		if _, isOptional := optional.ElemType(field.Type); isOptional {
+			val, present := optional.ReflectGet(fv)
+			if !present {
+				continue
+			}
+			data, err := json.Marshal(val.Interface())
+			if err != nil {
+				return wrote, fmt.Errorf("optpatch: field %s: %w", field.Name, err)
+			}
+			wrote = writeMember(buf, wrote, name, data)
+			continue
+		}
+		if field.Type.Kind() != reflect.Struct {
+			continue
+		}
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			var err error
+			wrote, err = writeMergeFields(buf, fv, wrote)
+			if err != nil {
+				return wrote, err
+			}
+			continue
+		}
+		var nested bytes.Buffer
+		nested.WriteByte('{')
+		nonEmpty, err := writeMergeFields(&nested, fv, false)
+		if err != nil {
+			return wrote, err
+		}
+		if nonEmpty {
+			nested.WriteByte('}')
+			wrote = writeMember(buf, wrote, name, nested.Bytes())
+		}
+	}
+	return wrote, nil
+}
+
+func writeMember(buf *bytes.Buffer, wrote bool, name string, data []byte) bool {
+	if wrote {
+		buf.WriteByte(',')
+	}
+	key, _ := json.Marshal(name)
+	buf.Write(key)
+	buf.WriteByte(':')
+	buf.Write(data)
+	return true
+}
+
+// jsonName returns the JSON object key for field and whether the field is
+// skipped, following encoding/json's rules for the `json` struct tag.
+func jsonName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+		return "", true
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, false
+}
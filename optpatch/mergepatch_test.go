@@ -0,0 +1,115 @@
+package optpatch
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+type Meta struct {
+	Version optional.Optional[int] `json:"version"`
+}
+
+type AddressPatch struct {
+	Street optional.Optional[string] `json:"street"`
+	City   optional.Optional[string] `json:"city"`
+}
+
+type UserPatch struct {
+	Meta
+	Name     optional.Optional[string]            `json:"name"`
+	Nickname optional.Optional[*string]           `json:"nickname,omitempty"`
+	Age      optional.Optional[int]               `json:"age"`
+	Tags     optional.Optional[[]string]          `json:"tags"`
+	Labels   optional.Optional[map[string]string] `json:"labels"`
+	Address  AddressPatch                         `json:"address"`
+	Secret   optional.Optional[string]            `json:"-"`
+	Plain    string                               `json:"plain"`
+	Untagged optional.Optional[bool]
+	internal optional.Optional[string]
+}
+
+func TestMergePatchGolden(t *testing.T) {
+	nick := "neo"
+	tests := []struct {
+		name  string
+		patch any
+	}{
+		{"empty", UserPatch{}},
+		{"zero_values", UserPatch{
+			Name: optional.Of(""),
+			Age:  optional.Of(0),
+			Tags: optional.Of([]string{}),
+		}},
+		{"explicit_nulls", UserPatch{
+			Nickname: optional.Of[*string](nil),
+			Tags:     optional.Of[[]string](nil),
+			Labels:   optional.Of[map[string]string](nil),
+		}},
+		{"nested", &UserPatch{
+			Nickname: optional.Of(&nick),
+			Address:  AddressPatch{City: optional.Of("Paris")},
+		}},
+		{"embedded", UserPatch{
+			Meta:     Meta{Version: optional.Of(3)},
+			Untagged: optional.Of(false),
+		}},
+		{"ignored_fields", UserPatch{
+			Secret:   optional.Of("s3cr3t"),
+			Plain:    "plain",
+			internal: optional.Of("internal"),
+		}},
+		{"full", UserPatch{
+			Meta:     Meta{Version: optional.Of(1)},
+			Name:     optional.Of("Ada <admin>"),
+			Nickname: optional.Of(&nick),
+			Age:      optional.Of(36),
+			Tags:     optional.Of([]string{"a", "b"}),
+			Labels:   optional.Of(map[string]string{"z": "1", "a": "2"}),
+			Address:  AddressPatch{Street: optional.Of("1 Main St"), City: optional.Of("London")},
+			Untagged: optional.Of(true),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch(tt.patch)
+			if err != nil {
+				t.Fatalf("MergePatch = %v", err)
+			}
+			golden := filepath.Join("testdata", "mergepatch", tt.name+".json")
+			if *update {
+				if err := os.WriteFile(golden, append(got, '\n'), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, bytes.TrimSuffix(want, []byte("\n"))) {
+				t.Errorf("MergePatch =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestMergePatchErrors(t *testing.T) {
+	if _, err := MergePatch((*UserPatch)(nil)); err == nil {
+		t.Error("MergePatch(nil pointer) = nil error")
+	}
+	if _, err := MergePatch(42); err == nil {
+		t.Error("MergePatch(int) = nil error")
+	}
+	type bad struct {
+		F optional.Optional[func()] `json:"f"`
+	}
+	if _, err := MergePatch(bad{F: optional.Of(func() {})}); err == nil {
+		t.Error("MergePatch(func) = nil error")
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/sergei-bronnikov/go-optional/optproto
+
+go 1.23
+
+require (
+	github.com/sergei-bronnikov/go-optional v0.0.0
+	google.golang.org/protobuf v1.36.12
+)
+
+replace github.com/sergei-bronnikov/go-optional => ../
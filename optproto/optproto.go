@@ -0,0 +1,178 @@
+// Package optproto converts between Optionals and protobuf nullable values: the
+// google.protobuf wrapper types (StringValue, Int64Value, BoolValue, ...) and
+// proto3 optional scalar fields, which are generated as pointers.
+//
+// A nil wrapper or pointer converts to an empty Optional, and an empty Optional
+// converts back to nil.
+//
+// Example usage:
+//
+//	nickname := optproto.FromStringValue(req.GetNickname())
+//	resp.Nickname = optproto.ToStringValue(user.Nickname)
+//
+//	limit := optproto.FromPtr(req.Limit) // proto3 "optional int32 limit"
+//	resp.Limit = optproto.ToPtr(limit)
+package optproto
+
+import (
+	optional "github.com/sergei-bronnikov/go-optional"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// FromPtr converts a proto3 optional scalar field into an Optional, copying the value.
+func FromPtr[T any](p *T) optional.Optional[T] {
+	return optional.OfNullable(p)
+}
+
+// ToPtr converts an Optional into a proto3 optional scalar field. The returned
+// pointer refers to a copy of the value, or is nil if o is empty.
+func ToPtr[T any](o optional.Optional[T]) *T {
+	if val, ok := o.Get(); ok {
+		return &val
+	}
+	return nil
+}
+
+// FromStringValue converts a google.protobuf.StringValue into an Optional.
+func FromStringValue(v *wrapperspb.StringValue) optional.Optional[string] {
+	if v == nil {
+		return optional.Empty[string]()
+	}
+	return optional.Of(v.GetValue())
+}
+
+// ToStringValue converts an Optional into a google.protobuf.StringValue, or nil if o is empty.
+func ToStringValue(o optional.Optional[string]) *wrapperspb.StringValue {
+	if val, ok := o.Get(); ok {
+		return wrapperspb.String(val)
+	}
+	return nil
+}
+
+// FromInt32Value converts a google.protobuf.Int32Value into an Optional.
+func FromInt32Value(v *wrapperspb.Int32Value) optional.Optional[int32] {
+	if v == nil {
+		return optional.Empty[int32]()
+	}
+	return optional.Of(v.GetValue())
+}
+
+// ToInt32Value converts an Optional into a google.protobuf.Int32Value, or nil if o is empty.
+func ToInt32Value(o optional.Optional[int32]) *wrapperspb.Int32Value {
+	if val, ok := o.Get(); ok {
+		return wrapperspb.Int32(val)
+	}
+	return nil
+}
+
+// FromInt64Value converts a google.protobuf.Int64Value into an Optional.
+func FromInt64Value(v *wrapperspb.Int64Value) optional.Optional[int64] {
+	if v == nil {
+		return optional.Empty[int64]()
+	}
+	return optional.Of(v.GetValue())
+}
+
+// ToInt64Value converts an Optional into a google.protobuf.Int64Value, or nil if o is empty.
+func ToInt64Value(o optional.Optional[int64]) *wrapperspb.Int64Value {
+	if val, ok := o.Get(); ok {
+		return wrapperspb.Int64(val)
+	}
+	return nil
+}
+
+// FromUInt32Value converts a google.protobuf.UInt32Value into an Optional.
+func FromUInt32Value(v *wrapperspb.UInt32Value) optional.Optional[uint32] {
+	if v == nil {
+		return optional.Empty[uint32]()
+	}
+	return optional.Of(v.GetValue())
+}
+
+// ToUInt32Value converts an Optional into a google.protobuf.UInt32Value, or nil if o is empty.
+func ToUInt32Value(o optional.Optional[uint32]) *wrapperspb.UInt32Value {
+	if val, ok := o.Get(); ok {
+		return wrapperspb.UInt32(val)
+	}
+	return nil
+}
+
+// FromUInt64Value converts a google.protobuf.UInt64Value into an Optional.
+func FromUInt64Value(v *wrapperspb.UInt64Value) optional.Optional[uint64] {
+	if v == nil {
+		return optional.Empty[uint64]()
+	}
+	return optional.Of(v.GetValue())
+}
+
+// ToUInt64Value converts an Optional into a google.protobuf.UInt64Value, or nil if o is empty.
+func ToUInt64Value(o optional.Optional[uint64]) *wrapperspb.UInt64Value {
+	if val, ok := o.Get(); ok {
+		return wrapperspb.UInt64(val)
+	}
+	return nil
+}
+
+// FromFloatValue converts a google.protobuf.FloatValue into an Optional.
+func FromFloatValue(v *wrapperspb.FloatValue) optional.Optional[float32] {
+	if v == nil {
+		return optional.Empty[float32]()
+	}
+	return optional.Of(v.GetValue())
+}
+
+// ToFloatValue converts an Optional into a google.protobuf.FloatValue, or nil if o is empty.
+func ToFloatValue(o optional.Optional[float32]) *wrapperspb.FloatValue {
+	if val, ok := o.Get(); ok {
+		return wrapperspb.Float(val)
+	}
+	return nil
+}
+
+// FromDoubleValue converts a google.protobuf.DoubleValue into an Optional.
+func FromDoubleValue(v *wrapperspb.DoubleValue) optional.Optional[float64] {
+	if v == nil {
+		return optional.Empty[float64]()
+	}
+	return optional.Of(v.GetValue())
+}
+
+// ToDoubleValue converts an Optional into a google.protobuf.DoubleValue, or nil if o is empty.
+func ToDoubleValue(o optional.Optional[float64]) *wrapperspb.DoubleValue {
+	if val, ok := o.Get(); ok {
+		return wrapperspb.Double(val)
+	}
+	return nil
+}
+
+// FromBoolValue converts a google.protobuf.BoolValue into an Optional.
+func FromBoolValue(v *wrapperspb.BoolValue) optional.Optional[bool] {
+	if v == nil {
+		return optional.Empty[bool]()
+	}
+	return optional.Of(v.GetValue())
+}
+
+// ToBoolValue converts an Optional into a google.protobuf.BoolValue, or nil if o is empty.
+func ToBoolValue(o optional.Optional[bool]) *wrapperspb.BoolValue {
+	if val, ok := o.Get(); ok {
+		return wrapperspb.Bool(val)
+	}
+	return nil
+}
+
+// FromBytesValue converts a google.protobuf.BytesValue into an Optional.
+func FromBytesValue(v *wrapperspb.BytesValue) optional.Optional[[]byte] {
+	if v == nil {
+		return optional.Empty[[]byte]()
+	}
+	return optional.Of(v.GetValue())
+}
+
+// ToBytesValue converts an Optional into a google.protobuf.BytesValue, or nil if o is empty.
+func ToBytesValue(o optional.Optional[[]byte]) *wrapperspb.BytesValue {
+	if val, ok := o.Get(); ok {
+		return wrapperspb.Bytes(val)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package optproto
+
+import (
+	"math"
+	"testing"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// wrapperCase checks one wrapper type's From and To functions against a list
+// of values, always including the zero value, which must stay present.
+type wrapperCase[T any, W proto.Message] struct {
+	from   func(W) optional.Optional[T]
+	to     func(optional.Optional[T]) W
+	wrap   func(T) W
+	values []T
+}
+
+func (c wrapperCase[T, W]) run(t *testing.T) {
+	var nilWrapper W
+	if got := c.from(nilWrapper); got.IsPresent() {
+		t.Errorf("From(nil) = %v, want empty", got)
+	}
+	if got := c.to(optional.Empty[T]()); !isNil(got) {
+		t.Errorf("To(empty) = %v, want nil", got)
+	}
+	var zero T
+	for _, v := range append([]T{zero}, c.values...) {
+		if got := c.from(c.wrap(v)); !got.Equals(optional.Of(v)) {
+			t.Errorf("From(%v) = %v, want Optional[%v]", v, got, v)
+		}
+		got := c.to(optional.Of(v))
+		if isNil(got) || !proto.Equal(got, c.wrap(v)) {
+			t.Errorf("To(Optional[%v]) = %v, want %v", v, got, c.wrap(v))
+		}
+		if back := c.from(c.to(optional.Of(v))); !back.Equals(optional.Of(v)) {
+			t.Errorf("round trip of %v = %v", v, back)
+		}
+	}
+}
+
+func isNil[W proto.Message](w W) bool {
+	return !w.ProtoReflect().IsValid()
+}
+
+func TestWrapperConversions(t *testing.T) {
+	tests := map[string]interface{ run(*testing.T) }{
+		"StringValue": wrapperCase[string, *wrapperspb.StringValue]{
+			FromStringValue, ToStringValue, wrapperspb.String, []string{"hello", "ünïcode"},
+		},
+		"Int32Value": wrapperCase[int32, *wrapperspb.Int32Value]{
+			FromInt32Value, ToInt32Value, wrapperspb.Int32, []int32{-1, math.MinInt32, math.MaxInt32},
+		},
+		"Int64Value": wrapperCase[int64, *wrapperspb.Int64Value]{
+			FromInt64Value, ToInt64Value, wrapperspb.Int64, []int64{-1, math.MinInt64, math.MaxInt64},
+		},
+		"UInt32Value": wrapperCase[uint32, *wrapperspb.UInt32Value]{
+			FromUInt32Value, ToUInt32Value, wrapperspb.UInt32, []uint32{1, math.MaxUint32},
+		},
+		"UInt64Value": wrapperCase[uint64, *wrapperspb.UInt64Value]{
+			FromUInt64Value, ToUInt64Value, wrapperspb.UInt64, []uint64{1, math.MaxUint64},
+		},
+		"FloatValue": wrapperCase[float32, *wrapperspb.FloatValue]{
+			FromFloatValue, ToFloatValue, wrapperspb.Float, []float32{-1.5, math.MaxFloat32, float32(math.Inf(1))},
+		},
+		"DoubleValue": wrapperCase[float64, *wrapperspb.DoubleValue]{
+			FromDoubleValue, ToDoubleValue, wrapperspb.Double, []float64{-1.5, math.SmallestNonzeroFloat64, math.Inf(-1)},
+		},
+		"BoolValue": wrapperCase[bool, *wrapperspb.BoolValue]{
+			FromBoolValue, ToBoolValue, wrapperspb.Bool, []bool{true},
+		},
+		"BytesValue": wrapperCase[[]byte, *wrapperspb.BytesValue]{
+			FromBytesValue, ToBytesValue, wrapperspb.Bytes, [][]byte{{}, {0, 1, 2}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, tc.run)
+	}
+}
+
+func TestPtrConversions(t *testing.T) {
+	if got := FromPtr[int32](nil); got.IsPresent() {
+		t.Errorf("FromPtr(nil) = %v, want empty", got)
+	}
+	if got := ToPtr(optional.Empty[int32]()); got != nil {
+		t.Errorf("ToPtr(empty) = %v, want nil", *got)
+	}
+
+	zero := int32(0)
+	if got := FromPtr(&zero); !got.Equals(optional.Of(int32(0))) {
+		t.Errorf("FromPtr(&0) = %v, want present zero", got)
+	}
+
+	v := "x"
+	o := FromPtr(&v)
+	v = "changed"
+	if !o.Equals(optional.Of("x")) {
+		t.Errorf("FromPtr aliases the field: %v", o)
+	}
+
+	src := optional.Of("y")
+	p := ToPtr(src)
+	if p == nil || *p != "y" {
+		t.Fatalf("ToPtr = %v, want a pointer to y", p)
+	}
+	*p = "changed"
+	if !src.Equals(optional.Of("y")) {
+		t.Errorf("ToPtr aliases the Optional: %v", src)
+	}
+}
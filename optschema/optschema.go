@@ -0,0 +1,67 @@
+// Package optschema documents Optionals in JSON Schemas generated with
+// invopop/jsonschema. Without it, an Optional field is reflected as an opaque
+// object with no properties; once registered, Optional[T] is described by the
+// schema of T or null.
+//
+// The schema describes JSON produced by optjson.Marshal, which encodes a
+// present Optional as its value and omits an empty Optional struct field
+// entirely. It does not describe the output of encoding/json, which encodes
+// every Optional as {} whatever it holds. The null branch covers empty
+// Optionals that optjson encodes as null, such as slice elements, and
+// decoders that read null as empty.
+//
+// Whether the field is required is decided by the reflector as usual. Since
+// optjson omits empty fields whatever their tag, tag Optional fields with
+// omitzero, or omitempty, to mark them not required.
+//
+// Example usage:
+//
+//	type User struct {
+//	    ID       int                       `json:"id"`
+//	    Nickname optional.Optional[string] `json:"nickname,omitzero"`
+//	}
+//
+//	r := &jsonschema.Reflector{}
+//	optschema.RegisterOptional(r)
+//	schema := r.Reflect(&User{})
+//	// "nickname": {"anyOf": [{"type": "string"}, {"type": "null"}]}, not required
+package optschema
+
+import (
+	"reflect"
+
+	"github.com/invopop/jsonschema"
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// RegisterOptional installs a Mapper on r that reflects every Optional[T] as
+// {"anyOf": [<schema of T>, {"type": "null"}]}. An existing Mapper on r is
+// kept and consulted first for non-Optional types.
+//
+// The schema of T is reflected with the settings of r, except that it is
+// always inlined rather than referenced from the definitions of the enclosing
+// schema, since a Mapper cannot add definitions.
+func RegisterOptional(r *jsonschema.Reflector) {
+	next := r.Mapper
+	var mapper func(reflect.Type) *jsonschema.Schema
+	mapper = func(t reflect.Type) *jsonschema.Schema {
+		elem, ok := optional.ElemType(t)
+		if !ok {
+			if next != nil {
+				return next(t)
+			}
+			return nil
+		}
+		sub := *r
+		sub.Mapper = mapper
+		sub.DoNotReference = true
+		sub.Anonymous = true
+		s := sub.ReflectFromType(elem)
+		s.Version = ""
+		s.Definitions = nil
+		return &jsonschema.Schema{
+			AnyOf: []*jsonschema.Schema{s, {Type: "null"}},
+		}
+	}
+	r.Mapper = mapper
+}
@@ -0,0 +1,164 @@
+package optschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/invopop/jsonschema"
+	optional "github.com/sergei-bronnikov/go-optional"
+	"github.com/sergei-bronnikov/go-optional/optjson"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+type Address struct {
+	City string                    `json:"city"`
+	Zip  optional.Optional[string] `json:"zip,omitempty"`
+}
+
+type User struct {
+	ID       int                         `json:"id"`
+	Nickname optional.Optional[string]   `json:"nickname,omitzero"`
+	Age      optional.Optional[int]      `json:"age"`
+	Tags     optional.Optional[[]string] `json:"tags,omitempty"`
+	Address  optional.Optional[Address]  `json:"address,omitzero"`
+	Home     Address                     `json:"home"`
+	Timeout  optional.Optional[time.Duration]
+}
+
+// durationMapper stands for a Mapper installed before RegisterOptional.
+func durationMapper(t reflect.Type) *jsonschema.Schema {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return &jsonschema.Schema{Type: "string", Format: "duration"}
+	}
+	return nil
+}
+
+func TestRegisterOptionalSnapshot(t *testing.T) {
+	tests := map[string]*jsonschema.Reflector{
+		"user":         {Mapper: durationMapper},
+		"user_inline":  {Mapper: durationMapper, DoNotReference: true},
+		"user_relaxed": {Mapper: durationMapper, AllowAdditionalProperties: true, RequiredFromJSONSchemaTags: true},
+	}
+	for name, r := range tests {
+		t.Run(name, func(t *testing.T) {
+			RegisterOptional(r)
+			got, err := json.MarshalIndent(r.Reflect(&User{}), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+			golden := filepath.Join("testdata", name+".json")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("schema differs from %s; rerun with -update and review the diff\ngot:\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func TestRegisterOptionalWithoutMapper(t *testing.T) {
+	r := &jsonschema.Reflector{}
+	RegisterOptional(r)
+	if s := r.Mapper(reflect.TypeOf(0)); s != nil {
+		t.Errorf("Mapper(int) = %v, want nil", s)
+	}
+	s := r.Mapper(reflect.TypeOf(optional.Optional[bool]{}))
+	if s == nil || len(s.AnyOf) != 2 || s.AnyOf[0].Type != "boolean" || s.AnyOf[1].Type != "null" {
+		t.Errorf("Mapper(Optional[bool]) = %+v, want boolean or null", s)
+	}
+}
+
+// jsonType returns the JSON Schema type of a value decoded by encoding/json
+// into an any.
+func jsonType(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// optionalTypes returns the JSON type of each Optional property of schema
+// present in data, keyed by property name.
+func optionalTypes(t *testing.T, schema *jsonschema.Schema, data []byte) map[string]string {
+	t.Helper()
+	var obj map[string]any
+	if err := json.Unmarshal(data, &obj); err != nil {
+		t.Fatal(err)
+	}
+	types := make(map[string]string)
+	for p := schema.Properties.Oldest(); p != nil; p = p.Next() {
+		v, ok := obj[p.Key]
+		if !ok || len(p.Value.AnyOf) == 0 {
+			continue
+		}
+		types[p.Key] = jsonType(v)
+	}
+	return types
+}
+
+func TestSchemaDescribesOptjson(t *testing.T) {
+	r := &jsonschema.Reflector{DoNotReference: true}
+	RegisterOptional(r)
+	schema := r.Reflect(&User{})
+	want := make(map[string]string)
+	for p := schema.Properties.Oldest(); p != nil; p = p.Next() {
+		if len(p.Value.AnyOf) > 0 {
+			want[p.Key] = p.Value.AnyOf[0].Type
+		}
+	}
+
+	u := User{
+		ID:       1,
+		Nickname: optional.Of("neo"),
+		Age:      optional.Of(36),
+		Tags:     optional.Of([]string{"a"}),
+		Address:  optional.Of(Address{City: "Paris"}),
+		Timeout:  optional.Of(time.Second),
+	}
+	data, err := optjson.Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := optionalTypes(t, schema, data); !reflect.DeepEqual(got, want) {
+		t.Errorf("optjson output types = %v, want the schema's %v", got, want)
+	}
+
+	data, err = json.Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, typ := range optionalTypes(t, schema, data) {
+		if typ != "object" {
+			t.Errorf("encoding/json encoded %s as %s, want the opaque object {}", name, typ)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package optsql builds SQL statements from structs of Optional fields, such
+// as the patch structs decoded from PATCH requests: only present fields become
+// part of the statement.
+//
+// Example usage:
+//
+//	type UserPatch struct {
+//	    Name  optional.Optional[string] `db:"name"`
+//	    Email optional.Optional[string] `db:"email"`
+//	}
+//
+//	query, args, err := optsql.BuildUpdate("users", patch, "id = ?", id)
+//	// UPDATE users SET email = $1 WHERE id = $2, with args [email, id]
+//	_, err = db.ExecContext(ctx, query, args...)
+package optsql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// ErrNoFields is returned, wrapped, when a patch has no present fields, which
+// would otherwise produce an UPDATE statement without a SET clause.
+var ErrNoFields = errors.New("optsql: no present fields to update")
+
+// Placeholder is a bind parameter style.
+type Placeholder int
+
+const (
+	// Dollar numbers parameters as $1, $2, ..., as PostgreSQL expects.
+	Dollar Placeholder = iota
+	// Question writes every parameter as ?, as MySQL and SQLite expect.
+	Question
+)
+
+// BuildUpdate is Dollar.BuildUpdate.
+func BuildUpdate(table string, patch any, where string, whereArgs ...any) (query string, args []any, err error) {
+	return Dollar.BuildUpdate(table, patch, where, whereArgs...)
+}
+
+// BuildUpdate returns an UPDATE statement for table that sets a column for
+// every present Optional field of patch, a struct or a pointer to one, and
+// the arguments to execute it with. Columns appear in field declaration order,
+// followed by the WHERE clause; where may be empty to update every row.
+//
+// The column of a field is the name in its `db` struct tag, up to the first
+// comma so that options used by other libraries are ignored, or its
+// lower-cased name if the tag has no name; a name of "-" skips the field.
+// Embedded structs are flattened, and fields that are not Optionals are
+// ignored. Table and column names are inserted verbatim, so they must not
+// come from untrusted input.
+//
+// where must write its parameters as ?, whatever the placeholder style; with
+// Dollar they are renumbered to follow the SET parameters. Question marks
+// inside single-quoted string literals are left alone.
+//
+// If no field is present, BuildUpdate returns an error wrapping ErrNoFields.
+func (p Placeholder) BuildUpdate(table string, patch any, where string, whereArgs ...any) (query string, args []any, err error) {
+	v := reflect.ValueOf(patch)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil, errors.New("optsql: patch is a nil pointer")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", nil, fmt.Errorf("optsql: patch must be a struct, got %T", patch)
+	}
+
+	var b strings.Builder
+	b.WriteString("UPDATE ")
+	b.WriteString(table)
+	b.WriteString(" SET ")
+	args = collectColumns(v, p, &b, args)
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("%w in %s", ErrNoFields, v.Type())
+	}
+	if where != "" {
+		b.WriteString(" WHERE ")
+		n := len(args)
+		for i := 0; i < len(where); i++ {
+			c := where[i]
+			if c == '\'' {
+				end := strings.IndexByte(where[i+1:], '\'')
+				if end < 0 {
+					b.WriteString(where[i:])
+					break
+				}
+				b.WriteString(where[i : i+end+2])
+				i += end + 1
+				continue
+			}
+			if c == '?' {
+				n++
+				b.WriteString(p.placeholder(n))
+				continue
+			}
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), append(args, whereArgs...), nil
+}
+
+func collectColumns(v reflect.Value, p Placeholder, b *strings.Builder, args []any) []any {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		column, _, _ := strings.Cut(field.Tag.Get("db"), ",")
+		if column == "-" {
+			continue
+		}
+		_, isOptional := optional.ElemType(field.Type)
+		if field.Anonymous && column == "" && field.Type.Kind() == reflect.Struct && !isOptional {
+			args = collectColumns(v.Field(i), p, b, args)
+			continue
+		}
+		if field.PkgPath != "" || !isOptional {
+			continue
+		}
+		val, present := optional.ReflectGet(v.Field(i))
+		if !present {
+			continue
+		}
+		if column == "" {
+			column = strings.ToLower(field.Name)
+		}
+		if len(args) > 0 {
+			b.WriteString(", ")
+		}
+		args = append(args, val.Interface())
+		b.WriteString(column)
+		b.WriteString(" = ")
+		b.WriteString(p.placeholder(len(args)))
+	}
+	return args
+}
+
+func (p Placeholder) placeholder(n int) string {
+	if p == Question {
+		return "?"
+	}
+	return "$" + strconv.Itoa(n)
+}
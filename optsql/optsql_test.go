@@ -0,0 +1,140 @@
+package optsql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+type Audit struct {
+	UpdatedBy optional.Optional[string] `db:"updated_by"`
+}
+
+type userPatch struct {
+	Name     optional.Optional[string] `db:"name"`
+	Email    optional.Optional[string] `db:"email,omitempty"`
+	Age      optional.Optional[int]
+	Password optional.Optional[string] `db:"-"`
+	Secret   optional.Optional[string] `db:"-,"`
+	Note     optional.Optional[string] `db:",omitempty"`
+	Plain    string                    `db:"plain"`
+	hidden   optional.Optional[string] `db:"hidden"`
+	Audit
+}
+
+func TestBuildUpdate(t *testing.T) {
+	tests := []struct {
+		name      string
+		style     Placeholder
+		patch     any
+		where     string
+		whereArgs []any
+		wantQuery string
+		wantArgs  []any
+	}{
+		{
+			name:      "one field",
+			patch:     userPatch{Name: optional.Of("ada")},
+			where:     "id = ?",
+			whereArgs: []any{7},
+			wantQuery: "UPDATE users SET name = $1 WHERE id = $2",
+			wantArgs:  []any{"ada", 7},
+		},
+		{
+			name:      "tag options are ignored",
+			patch:     &userPatch{Email: optional.Of("a@b.c"), Note: optional.Of("n")},
+			wantQuery: "UPDATE users SET email = $1, note = $2",
+			wantArgs:  []any{"a@b.c", "n"},
+		},
+		{
+			name: "declaration order and embedded fields",
+			patch: userPatch{
+				Name: optional.Of("ada"), Age: optional.Of(0), Email: optional.Of(""),
+				Audit: Audit{UpdatedBy: optional.Of("admin")},
+			},
+			where:     "id = ? AND tenant = ?",
+			whereArgs: []any{7, "t1"},
+			wantQuery: "UPDATE users SET name = $1, email = $2, age = $3, updated_by = $4 WHERE id = $5 AND tenant = $6",
+			wantArgs:  []any{"ada", "", 0, "admin", 7, "t1"},
+		},
+		{
+			name:      "skipped and ignored fields",
+			patch:     userPatch{Password: optional.Of("p"), Secret: optional.Of("s"), Plain: "x", hidden: optional.Of("h"), Age: optional.Of(1)},
+			wantQuery: "UPDATE users SET age = $1",
+			wantArgs:  []any{1},
+		},
+		{
+			name:      "question placeholders",
+			style:     Question,
+			patch:     userPatch{Name: optional.Of("ada"), Age: optional.Of(3)},
+			where:     "id = ?",
+			whereArgs: []any{7},
+			wantQuery: "UPDATE users SET name = ?, age = ? WHERE id = ?",
+			wantArgs:  []any{"ada", 3, 7},
+		},
+		{
+			name:      "question marks in string literals",
+			patch:     userPatch{Name: optional.Of("ada")},
+			where:     "note <> 'why?' AND id = ?",
+			whereArgs: []any{7},
+			wantQuery: "UPDATE users SET name = $1 WHERE note <> 'why?' AND id = $2",
+			wantArgs:  []any{"ada", 7},
+		},
+		{
+			name:      "unterminated literal",
+			patch:     userPatch{Name: optional.Of("ada")},
+			where:     "note = 'open ?",
+			wantQuery: "UPDATE users SET name = $1 WHERE note = 'open ?",
+			wantArgs:  []any{"ada"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := tt.style.BuildUpdate("users", tt.patch, tt.where, tt.whereArgs...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("query = %q\nwant    %q", query, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+
+	query, _, err := BuildUpdate("users", userPatch{Name: optional.Of("ada")}, "id = ?", 1)
+	if err != nil || query != "UPDATE users SET name = $1 WHERE id = $2" {
+		t.Errorf("BuildUpdate = %q, %v, want Dollar placeholders", query, err)
+	}
+}
+
+func TestBuildUpdateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   any
+		wantErr string
+		is      error
+	}{
+		{"no present fields", userPatch{Plain: "x"}, "optsql: no present fields to update in optsql.userPatch", ErrNoFields},
+		{"only skipped fields", userPatch{Password: optional.Of("p")}, "", ErrNoFields},
+		{"nil pointer", (*userPatch)(nil), "optsql: patch is a nil pointer", nil},
+		{"not a struct", map[string]any{"name": "ada"}, "optsql: patch must be a struct, got map[string]interface {}", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := BuildUpdate("users", tt.patch, "id = ?", 1)
+			if err == nil || query != "" || args != nil {
+				t.Fatalf("BuildUpdate = %q, %v, %v, want an error", query, args, err)
+			}
+			if tt.wantErr != "" && err.Error() != tt.wantErr {
+				t.Errorf("error = %q, want %q", err, tt.wantErr)
+			}
+			if tt.is != nil && !errors.Is(err, tt.is) {
+				t.Errorf("error = %v, want one wrapping %v", err, tt.is)
+			}
+		})
+	}
+}
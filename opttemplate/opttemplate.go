@@ -0,0 +1,120 @@
+// Package opttemplate provides template functions for rendering Optional fields
+// with text/template and html/template.
+//
+// Example usage:
+//
+//	tmpl := template.New("greeting").Funcs(opttemplate.FuncMap())
+//	tmpl = template.Must(tmpl.Parse(
+//	    `Hello, {{ orElse .Nickname "anonymous" }}!` +
+//	        `{{ if isPresent .Email }} We will write to {{ value .Email }}.{{ end }}`))
+//
+// The functions accept any Optional instantiation, held by value or by pointer.
+// A nil pointer to an Optional is treated as empty.
+package opttemplate
+
+import (
+	"fmt"
+	"reflect"
+	"text/template"
+)
+
+// optionalValue is the subset of the Optional method set used by the template
+// functions. It is satisfied by every *Optional[T].
+type optionalValue interface {
+	IsPresent() bool
+	OrNil() any
+}
+
+// FuncMap returns the template functions provided by this package:
+//
+//   - isPresent OPT: reports whether OPT holds a value
+//   - isEmpty OPT: reports whether OPT holds no value
+//   - value OPT: returns the value of OPT, or nil if empty
+//   - orElse OPT DEFAULT: returns the value of OPT, or DEFAULT if empty
+//   - deref OPT: returns the value of OPT, or the zero value of its type if empty
+//
+// The returned map can be passed to the Funcs method of both text/template and
+// html/template.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"isPresent": isPresent,
+		"isEmpty":   isEmpty,
+		"value":     value,
+		"orElse":    orElse,
+		"deref":     deref,
+	}
+}
+
+func isPresent(arg any) (bool, error) {
+	o, err := asOptional(arg)
+	if err != nil {
+		return false, err
+	}
+	return o != nil && o.IsPresent(), nil
+}
+
+func isEmpty(arg any) (bool, error) {
+	present, err := isPresent(arg)
+	return !present, err
+}
+
+func value(arg any) (any, error) {
+	o, err := asOptional(arg)
+	if err != nil || o == nil {
+		return nil, err
+	}
+	return o.OrNil(), nil
+}
+
+func orElse(arg any, def any) (any, error) {
+	o, err := asOptional(arg)
+	if err != nil {
+		return nil, err
+	}
+	if o == nil || !o.IsPresent() {
+		return def, nil
+	}
+	return o.OrNil(), nil
+}
+
+func deref(arg any) (any, error) {
+	o, err := asOptional(arg)
+	if err != nil {
+		return nil, err
+	}
+	if o != nil && o.IsPresent() {
+		return o.OrNil(), nil
+	}
+	// The element type is the parameter type of OrElse, which every
+	// Optional[T] exposes as OrElse(T) T.
+	rv := reflect.ValueOf(arg)
+	if rv.Kind() == reflect.Ptr {
+		rv = reflect.New(rv.Type().Elem()).Elem()
+	}
+	method := rv.MethodByName("OrElse")
+	if !method.IsValid() || method.Type().NumIn() != 1 {
+		return nil, nil
+	}
+	return reflect.Zero(method.Type().In(0)).Interface(), nil
+}
+
+// asOptional returns arg as an optionalValue. A nil pointer to an Optional
+// yields a nil optionalValue and no error. An Optional held by value is
+// copied, since its presence checks have pointer receivers.
+func asOptional(arg any) (optionalValue, error) {
+	rv := reflect.ValueOf(arg)
+	target := arg
+	if rv.IsValid() && rv.Kind() != reflect.Ptr {
+		ptr := reflect.New(rv.Type())
+		ptr.Elem().Set(rv)
+		target = ptr.Interface()
+	}
+	o, ok := target.(optionalValue)
+	if !ok {
+		return nil, fmt.Errorf("opttemplate: expected an Optional, got %T", arg)
+	}
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, nil
+	}
+	return o, nil
+}
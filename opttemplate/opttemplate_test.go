@@ -0,0 +1,114 @@
+package opttemplate
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+type profile struct {
+	Nickname optional.Optional[string]
+	Email    *optional.Optional[string]
+	Age      optional.Optional[int]
+	Plain    string
+}
+
+var (
+	full = profile{
+		Nickname: optional.Of("<ada>"),
+		Email:    ptr(optional.Of("ada@example.com")),
+		Age:      optional.Of(36),
+		Plain:    "plain",
+	}
+	blank = profile{Plain: "plain"}
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestFuncs(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl string
+		data profile
+		want string
+	}{
+		{"isPresent present", `{{ isPresent .Nickname }}`, full, "true"},
+		{"isPresent empty", `{{ isPresent .Nickname }}`, blank, "false"},
+		{"isPresent pointer", `{{ isPresent .Email }}`, full, "true"},
+		{"isPresent nil pointer", `{{ isPresent .Email }}`, blank, "false"},
+		{"isEmpty present", `{{ isEmpty .Age }}`, full, "false"},
+		{"isEmpty empty", `{{ isEmpty .Age }}`, blank, "true"},
+		{"isEmpty nil pointer", `{{ isEmpty .Email }}`, blank, "true"},
+		{"value present", `{{ value .Age }}`, full, "36"},
+		{"value empty", `{{ value .Age }}`, blank, "<no value>"},
+		{"value nil pointer", `{{ value .Email }}`, blank, "<no value>"},
+		{"orElse present", `{{ orElse .Email "none" }}`, full, "ada@example.com"},
+		{"orElse empty", `{{ orElse .Nickname "anonymous" }}`, blank, "anonymous"},
+		{"orElse nil pointer", `{{ orElse .Email "none" }}`, blank, "none"},
+		{"deref present", `{{ deref .Age }}`, full, "36"},
+		{"deref empty", `{{ deref .Age }}`, blank, "0"},
+		{"deref empty string", `[{{ deref .Nickname }}]`, blank, "[]"},
+		{"deref nil pointer", `[{{ deref .Email }}]`, blank, "[]"},
+		{"arithmetic on deref", `{{ if gt (deref .Age) 18 }}adult{{ else }}minor{{ end }}`, blank, "minor"},
+		{"if isPresent", `Hello, {{ orElse .Nickname "anonymous" }}!{{ if isPresent .Email }} Mail {{ value .Email }}.{{ end }}`, full, "Hello, <ada>! Mail ada@example.com."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New(tt.name).Funcs(FuncMap()).Parse(tt.tmpl))
+			var b strings.Builder
+			if err := tmpl.Execute(&b, tt.data); err != nil {
+				t.Fatal(err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLTemplate(t *testing.T) {
+	tmpl := htmltemplate.Must(htmltemplate.New("page").Funcs(htmltemplate.FuncMap(FuncMap())).Parse(
+		`<p>{{ orElse .Nickname "anonymous" }}</p>{{ if isEmpty .Email }}<i>no mail</i>{{ end }}<b>{{ deref .Age }}</b>`))
+	tests := []struct {
+		data profile
+		want string
+	}{
+		{full, "<p>&lt;ada&gt;</p><b>36</b>"},
+		{blank, "<p>anonymous</p><i>no mail</i><b>0</b>"},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := tmpl.Execute(&b, tt.data); err != nil {
+			t.Fatal(err)
+		}
+		if got := b.String(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestNotAnOptional(t *testing.T) {
+	for _, fn := range []string{"isPresent", "isEmpty", "value", "orElse", "deref"} {
+		t.Run(fn, func(t *testing.T) {
+			src := `{{ ` + fn + ` .Plain }}`
+			if fn == "orElse" {
+				src = `{{ orElse .Plain "x" }}`
+			}
+			tmpl := template.Must(template.New(fn).Funcs(FuncMap()).Parse(src))
+			err := tmpl.Execute(new(strings.Builder), blank)
+			if err == nil || !strings.Contains(err.Error(), "opttemplate: expected an Optional, got string") {
+				t.Errorf("Execute error = %v, want the asOptional error", err)
+			}
+		})
+	}
+
+	if _, err := isPresent(nil); err == nil || !strings.Contains(err.Error(), "got <nil>") {
+		t.Errorf("isPresent(nil) error = %v", err)
+	}
+	if _, err := deref(new(int)); err == nil {
+		t.Error("deref(*int) succeeded")
+	}
+}
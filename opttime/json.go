@@ -0,0 +1,98 @@
+package opttime
+
+import (
+	"encoding/json"
+	"time"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// Layout names a time layout for Time. Implement it on an empty struct type
+// to use a custom layout:
+//
+//	type German struct{}
+//
+//	func (German) Layout() string { return "02.01.2006" }
+//
+//	type Invoice struct {
+//	    Due opttime.Time[German] `json:"due"`
+//	}
+type Layout interface {
+	Layout() string
+}
+
+// RFC3339 is the Layout for time.RFC3339.
+type RFC3339 struct{}
+
+// Layout returns time.RFC3339.
+func (RFC3339) Layout() string { return time.RFC3339 }
+
+// RFC3339Nano is the Layout for time.RFC3339Nano.
+type RFC3339Nano struct{}
+
+// Layout returns time.RFC3339Nano.
+func (RFC3339Nano) Layout() string { return time.RFC3339Nano }
+
+// RFC1123 is the Layout for time.RFC1123.
+type RFC1123 struct{}
+
+// Layout returns time.RFC1123.
+func (RFC1123) Layout() string { return time.RFC1123 }
+
+// DateOnly is the Layout for dates such as "2006-01-02".
+type DateOnly struct{}
+
+// Layout returns "2006-01-02".
+func (DateOnly) Layout() string { return "2006-01-02" }
+
+// DateTime is the Layout for date-times such as "2006-01-02 15:04:05".
+type DateTime struct{}
+
+// Layout returns "2006-01-02 15:04:05".
+func (DateTime) Layout() string { return "2006-01-02 15:04:05" }
+
+// Time is an Optional[time.Time] that is encoded in JSON as a string in the
+// layout named by L, for external APIs that do not use RFC 3339. All Optional
+// methods are promoted, and the wrapped Optional is available as the Optional
+// field.
+//
+// An empty Time is encoded as null, and both null and an empty string decode
+// to an empty Time. A present zero time is encoded like any other time.
+//
+// Example:
+//
+//	type Event struct {
+//	    Day opttime.Time[opttime.DateOnly] `json:"day"` // "2024-01-02"
+//	}
+type Time[L Layout] struct {
+	optional.Optional[time.Time]
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t Time[L]) MarshalJSON() ([]byte, error) {
+	val, ok := t.Get()
+	if !ok {
+		return []byte("null"), nil
+	}
+	var l L
+	return json.Marshal(val.Format(l.Layout()))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Time[L]) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == nil || *s == "" {
+		t.Optional = optional.Empty[time.Time]()
+		return nil
+	}
+	var l L
+	val, err := time.Parse(l.Layout(), *s)
+	if err != nil {
+		return err
+	}
+	t.Optional = optional.Of(val)
+	return nil
+}
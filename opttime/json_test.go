@@ -0,0 +1,96 @@
+package opttime
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+type german struct{}
+
+func (german) Layout() string { return "02.01.2006" }
+
+type jsonCase[L Layout] struct {
+	in       optional.Optional[time.Time]
+	wantJSON string
+}
+
+// run marshals a struct holding c.in and unmarshals the result back,
+// expecting to get c.in again.
+func (c jsonCase[L]) run(t *testing.T) {
+	t.Helper()
+	type event struct {
+		At Time[L] `json:"at"`
+	}
+	data, err := json.Marshal(event{At: Time[L]{c.in}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"at":` + c.wantJSON + `}`; string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+	var got event
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	gv, gok := got.At.Get()
+	wv, wok := c.in.Get()
+	if gok != wok || !gv.Equal(wv) {
+		t.Errorf("round trip = %v, want %v", got.At.Optional, c.in)
+	}
+}
+
+func TestTimeJSON(t *testing.T) {
+	at := time.Date(2024, 1, 2, 15, 4, 5, 123456789, time.UTC)
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		run  func(*testing.T)
+	}{
+		{"RFC3339", jsonCase[RFC3339]{optional.Of(at.Truncate(time.Second)), `"2024-01-02T15:04:05Z"`}.run},
+		{"RFC3339Nano", jsonCase[RFC3339Nano]{optional.Of(at), `"2024-01-02T15:04:05.123456789Z"`}.run},
+		{"RFC1123", jsonCase[RFC1123]{optional.Of(at.Truncate(time.Second)), `"Tue, 02 Jan 2024 15:04:05 UTC"`}.run},
+		{"DateOnly", jsonCase[DateOnly]{optional.Of(day), `"2024-01-02"`}.run},
+		{"DateTime", jsonCase[DateTime]{optional.Of(at.Truncate(time.Second)), `"2024-01-02 15:04:05"`}.run},
+		{"custom", jsonCase[german]{optional.Of(day), `"02.01.2024"`}.run},
+		{"RFC3339 empty", jsonCase[RFC3339]{optional.Empty[time.Time](), `null`}.run},
+		{"DateOnly empty", jsonCase[DateOnly]{optional.Empty[time.Time](), `null`}.run},
+		{"RFC3339 zero", jsonCase[RFC3339]{optional.Of(time.Time{}), `"0001-01-01T00:00:00Z"`}.run},
+		{"DateOnly zero", jsonCase[DateOnly]{optional.Of(time.Time{}), `"0001-01-01"`}.run},
+		{"custom zero", jsonCase[german]{optional.Of(time.Time{}), `"01.01.0001"`}.run},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
+
+func TestTimeUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    optional.Optional[time.Time]
+		wantErr bool
+	}{
+		{`null`, optional.Empty[time.Time](), false},
+		{`""`, optional.Empty[time.Time](), false},
+		{`"2024-01-02"`, optional.Of(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)), false},
+		{`"0001-01-01"`, optional.Of(time.Time{}), false},
+		{`"2024-01-02T15:04:05Z"`, optional.Empty[time.Time](), true},
+		{`"02.01.2024"`, optional.Empty[time.Time](), true},
+		{`20240102`, optional.Empty[time.Time](), true},
+	}
+	for _, tt := range tests {
+		got := Time[DateOnly]{optional.Of(time.Now())}
+		err := got.UnmarshalJSON([]byte(tt.in))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("UnmarshalJSON(%s) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if tt.wantErr {
+			continue
+		}
+		if got.Optional != tt.want {
+			t.Errorf("UnmarshalJSON(%s) = %v, want %v", tt.in, got.Optional, tt.want)
+		}
+	}
+}
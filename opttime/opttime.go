@@ -0,0 +1,126 @@
+// Package opttime provides Optional constructors and helpers for times and
+// durations.
+//
+// An empty Optional[time.Time] and a present zero time.Time{} are different
+// values, and nothing in this package converts one into the other: a blank
+// string parses to empty, while a string that parses to the zero time is
+// present. The only exception is FromUnix, where 0 is the conventional "unset"
+// marker of Unix timestamps; it yields empty rather than the Unix epoch,
+// which is not the zero time.Time either.
+//
+// Example usage:
+//
+//	since := opttime.ParseTime([]string{time.RFC3339, "2006-01-02"}, r.FormValue("since"))
+//	ttl := opttime.ParseDuration(os.Getenv("CACHE_TTL"))
+//	expired := opttime.Before(token.ExpiresAt, optional.Of(time.Now()))
+package opttime
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// ParseTime parses s with each layout in turn and returns the first
+// successful result. Surrounding whitespace is trimmed, and a blank s or a
+// string matching none of the layouts yields an empty Optional.
+func ParseTime(layouts []string, s string) optional.Optional[time.Time] {
+	o, _ := TryParseTime(layouts, s)
+	return o
+}
+
+// TryParseTime is like ParseTime, but returns the error from the last layout
+// if none matches. A blank s yields an empty Optional without an error.
+func TryParseTime(layouts []string, s string) (optional.Optional[time.Time], error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return optional.Empty[time.Time](), nil
+	}
+	err := errors.New("opttime: no layouts given")
+	for _, layout := range layouts {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return optional.Of(t), nil
+		}
+	}
+	return optional.Empty[time.Time](), err
+}
+
+// ParseDuration parses s as time.ParseDuration does. Surrounding whitespace is
+// trimmed, and a blank or malformed s yields an empty Optional.
+func ParseDuration(s string) optional.Optional[time.Duration] {
+	o, _ := TryParseDuration(s)
+	return o
+}
+
+// TryParseDuration is like ParseDuration, but returns the parse error.
+func TryParseDuration(s string) (optional.Optional[time.Duration], error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return optional.Empty[time.Duration](), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return optional.Empty[time.Duration](), err
+	}
+	return optional.Of(d), nil
+}
+
+// UnixOption configures FromUnix.
+type UnixOption func(*unixConfig)
+
+type unixConfig struct {
+	negativeAsEmpty bool
+}
+
+// NegativeAsEmpty makes FromUnix treat negative timestamps as empty, for
+// sources that use -1 as an "unset" marker. By default they are present times
+// before 1970.
+func NegativeAsEmpty() UnixOption {
+	return func(c *unixConfig) {
+		c.negativeAsEmpty = true
+	}
+}
+
+// FromUnix returns the local time corresponding to sec seconds since the Unix
+// epoch, as time.Unix does, or an empty Optional if sec is 0.
+//
+// Example:
+//
+//	deletedAt := opttime.FromUnix(row.DeletedAt, opttime.NegativeAsEmpty())
+func FromUnix(sec int64, opts ...UnixOption) optional.Optional[time.Time] {
+	var cfg unixConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if sec == 0 || (sec < 0 && cfg.negativeAsEmpty) {
+		return optional.Empty[time.Time]()
+	}
+	return optional.Of(time.Unix(sec, 0))
+}
+
+// Before reports whether a is before b, or returns an empty Optional if either
+// is empty.
+func Before(a, b optional.Optional[time.Time]) optional.Optional[bool] {
+	return compare(a, b, time.Time.Before)
+}
+
+// After reports whether a is after b, or returns an empty Optional if either
+// is empty.
+func After(a, b optional.Optional[time.Time]) optional.Optional[bool] {
+	return compare(a, b, time.Time.After)
+}
+
+func compare(a, b optional.Optional[time.Time], cmp func(time.Time, time.Time) bool) optional.Optional[bool] {
+	x, ok := a.Get()
+	if !ok {
+		return optional.Empty[bool]()
+	}
+	y, ok := b.Get()
+	if !ok {
+		return optional.Empty[bool]()
+	}
+	return optional.Of(cmp(x, y))
+}
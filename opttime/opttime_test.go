@@ -0,0 +1,117 @@
+package opttime
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+func TestTryParseTime(t *testing.T) {
+	layouts := []string{time.RFC3339, "2006-01-02", "02.01.2006"}
+	tests := []struct {
+		name    string
+		layouts []string
+		in      string
+		want    optional.Optional[time.Time]
+		wantErr string
+	}{
+		{"first layout", layouts, "2024-01-02T15:04:05Z", optional.Of(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)), ""},
+		{"second layout", layouts, "2024-01-02", optional.Of(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)), ""},
+		{"last layout", layouts, " 02.01.2024\n", optional.Of(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)), ""},
+		{"zero time", layouts, "0001-01-01", optional.Of(time.Time{}), ""},
+		{"blank", layouts, "  ", optional.Empty[time.Time](), ""},
+		{"no match", layouts, "2024/01/02", optional.Empty[time.Time](), `parsing time "2024/01/02" as "02.01.2006"`},
+		{"no layouts", nil, "2024-01-02", optional.Empty[time.Time](), "opttime: no layouts given"},
+		{"no layouts blank", nil, "", optional.Empty[time.Time](), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TryParseTime(tt.layouts, tt.in)
+			if got != tt.want {
+				t.Errorf("TryParseTime = %v, want %v", got, tt.want)
+			}
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.wantErr)) {
+				t.Errorf("error = %v, want one starting with %q", err, tt.wantErr)
+			}
+			if lossy := ParseTime(tt.layouts, tt.in); lossy != tt.want {
+				t.Errorf("ParseTime = %v, want %v", lossy, tt.want)
+			}
+		})
+	}
+}
+
+func TestTryParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    optional.Optional[time.Duration]
+		wantErr bool
+	}{
+		{"1m30s", optional.Of(90 * time.Second), false},
+		{" 0s ", optional.Of(time.Duration(0)), false},
+		{"", optional.Empty[time.Duration](), false},
+		{"90", optional.Empty[time.Duration](), true},
+	}
+	for _, tt := range tests {
+		got, err := TryParseDuration(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("TryParseDuration(%q) = %v, %v, want %v, wantErr %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+		if lossy := ParseDuration(tt.in); lossy != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", tt.in, lossy, tt.want)
+		}
+	}
+}
+
+func TestFromUnix(t *testing.T) {
+	tests := []struct {
+		name string
+		sec  int64
+		opts []UnixOption
+		want optional.Optional[time.Time]
+	}{
+		{"positive", 1700000000, nil, optional.Of(time.Unix(1700000000, 0))},
+		{"zero", 0, nil, optional.Empty[time.Time]()},
+		{"negative", -1, nil, optional.Of(time.Unix(-1, 0))},
+		{"positive NegativeAsEmpty", 1700000000, []UnixOption{NegativeAsEmpty()}, optional.Of(time.Unix(1700000000, 0))},
+		{"zero NegativeAsEmpty", 0, []UnixOption{NegativeAsEmpty()}, optional.Empty[time.Time]()},
+		{"negative NegativeAsEmpty", -1, []UnixOption{NegativeAsEmpty()}, optional.Empty[time.Time]()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromUnix(tt.sec, tt.opts...); got != tt.want {
+				t.Errorf("FromUnix(%d) = %v, want %v", tt.sec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBeforeAfter(t *testing.T) {
+	early := optional.Of(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	late := optional.Of(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	none := optional.Empty[time.Time]()
+	tests := []struct {
+		name                  string
+		a, b                  optional.Optional[time.Time]
+		wantBefore, wantAfter optional.Optional[bool]
+	}{
+		{"early late", early, late, optional.Of(true), optional.Of(false)},
+		{"late early", late, early, optional.Of(false), optional.Of(true)},
+		{"equal", early, early, optional.Of(false), optional.Of(false)},
+		{"a empty", none, late, optional.Empty[bool](), optional.Empty[bool]()},
+		{"b empty", early, none, optional.Empty[bool](), optional.Empty[bool]()},
+		{"both empty", none, none, optional.Empty[bool](), optional.Empty[bool]()},
+		{"zero time is present", optional.Of(time.Time{}), early, optional.Of(true), optional.Of(false)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Before(tt.a, tt.b); got != tt.wantBefore {
+				t.Errorf("Before = %v, want %v", got, tt.wantBefore)
+			}
+			if got := After(tt.a, tt.b); got != tt.wantAfter {
+				t.Errorf("After = %v, want %v", got, tt.wantAfter)
+			}
+		})
+	}
+}
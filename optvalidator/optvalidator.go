@@ -0,0 +1,80 @@
+// Package optvalidator integrates Optionals with go-playground/validator. Once
+// registered, an empty Optional is treated like a nil pointer (skipped under
+// omitempty, failed under required) and a present Optional is validated as its
+// contained value.
+//
+// Example usage:
+//
+//	type SignupRequest struct {
+//	    Email    optional.Optional[string]   `validate:"required,email"`
+//	    Nickname optional.Optional[string]   `validate:"omitempty,min=3"`
+//	    Tags     []optional.Optional[string] `validate:"dive,omitempty,alpha"`
+//	}
+//
+//	v := validator.New()
+//	optvalidator.RegisterOptional(v)
+//	err := v.Struct(req)
+package optvalidator
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+// defaultTypes are the Optional instantiations registered by RegisterOptional
+// in addition to any caller-supplied types.
+var defaultTypes = []any{
+	optional.Optional[string]{},
+	optional.Optional[bool]{},
+	optional.Optional[int]{},
+	optional.Optional[int8]{},
+	optional.Optional[int16]{},
+	optional.Optional[int32]{},
+	optional.Optional[int64]{},
+	optional.Optional[uint]{},
+	optional.Optional[uint8]{},
+	optional.Optional[uint16]{},
+	optional.Optional[uint32]{},
+	optional.Optional[uint64]{},
+	optional.Optional[float32]{},
+	optional.Optional[float64]{},
+	optional.Optional[time.Time]{},
+	optional.Optional[time.Duration]{},
+	optional.Optional[[]byte]{},
+}
+
+// RegisterOptional registers a custom type function for Optionals of the
+// built-in scalar types, []byte, time.Time and time.Duration, plus any
+// additional Optional instantiations passed in types. The validator package
+// matches custom type functions by exact type, so Optionals of other element
+// types, such as Optional[Address], must be listed explicitly.
+//
+// As with plain fields, omitempty also skips present zero values. Fields of a
+// struct contained in a present Optional are not validated recursively; call
+// Validate.Struct on the contained value when that is needed.
+//
+// Like validator.Validate.RegisterCustomTypeFunc, RegisterOptional is not safe
+// for concurrent use and should be called before any validation.
+//
+// Example:
+//
+//	optvalidator.RegisterOptional(v, optional.Optional[Address]{})
+func RegisterOptional(v *validator.Validate, types ...any) {
+	all := make([]any, 0, len(defaultTypes)+len(types))
+	all = append(all, defaultTypes...)
+	all = append(all, types...)
+	v.RegisterCustomTypeFunc(ValidateValuer, all...)
+}
+
+// ValidateValuer is the validator.CustomTypeFunc used by RegisterOptional. It
+// returns the value contained in the Optional field, or nil if it is empty,
+// which the validator treats like a nil pointer.
+func ValidateValuer(field reflect.Value) any {
+	if val, ok := optional.ReflectGet(field); ok {
+		return val.Interface()
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package optvalidator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	optional "github.com/sergei-bronnikov/go-optional"
+)
+
+type address struct {
+	City string
+}
+
+type signup struct {
+	Email    optional.Optional[string]   `validate:"required,email"`
+	Nickname optional.Optional[string]   `validate:"omitempty,min=3"`
+	Age      optional.Optional[int]      `validate:"omitempty,gte=18"`
+	Tags     []optional.Optional[string] `validate:"dive,omitempty,alpha"`
+	Scores   []optional.Optional[int]    `validate:"dive,required,lte=100"`
+	Home     optional.Optional[address]  `validate:"required"`
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	RegisterOptional(v, optional.Optional[address]{})
+	return v
+}
+
+// valid returns a signup that passes validation, for tests to break one field.
+func valid() signup {
+	return signup{
+		Email: optional.Of("ada@example.com"),
+		Home:  optional.Of(address{City: "London"}),
+	}
+}
+
+func TestValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*signup)
+		failed string // namespace of the failing field, or "" if valid
+		tag    string
+	}{
+		{"valid", func(*signup) {}, "", ""},
+		{"required empty", func(s *signup) { s.Email = optional.Empty[string]() }, "signup.Email", "required"},
+		{"required present zero", func(s *signup) { s.Email = optional.Of("") }, "signup.Email", "required"},
+		{"required present invalid", func(s *signup) { s.Email = optional.Of("nope") }, "signup.Email", "email"},
+		{"required struct empty", func(s *signup) { s.Home = optional.Empty[address]() }, "signup.Home", "required"},
+		{"omitempty empty", func(s *signup) { s.Nickname = optional.Empty[string]() }, "", ""},
+		{"omitempty present zero", func(s *signup) { s.Nickname = optional.Of("") }, "", ""},
+		{"omitempty present valid", func(s *signup) { s.Nickname = optional.Of("ada") }, "", ""},
+		{"omitempty present invalid", func(s *signup) { s.Nickname = optional.Of("a") }, "signup.Nickname", "min"},
+		{"omitempty int invalid", func(s *signup) { s.Age = optional.Of(17) }, "signup.Age", "gte"},
+		{"dive omitempty valid", func(s *signup) {
+			s.Tags = []optional.Optional[string]{optional.Of("go"), optional.Empty[string]()}
+		}, "", ""},
+		{"dive omitempty invalid", func(s *signup) {
+			s.Tags = []optional.Optional[string]{optional.Empty[string](), optional.Of("go1")}
+		}, "signup.Tags[1]", "alpha"},
+		{"dive required empty element", func(s *signup) {
+			s.Scores = []optional.Optional[int]{optional.Of(1), optional.Empty[int]()}
+		}, "signup.Scores[1]", "required"},
+		{"dive required invalid element", func(s *signup) {
+			s.Scores = []optional.Optional[int]{optional.Of(101)}
+		}, "signup.Scores[0]", "lte"},
+	}
+	v := newValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := valid()
+			tt.modify(&s)
+			err := v.Struct(s)
+			if tt.failed == "" {
+				if err != nil {
+					t.Fatalf("Struct = %v, want nil", err)
+				}
+				return
+			}
+			var errs validator.ValidationErrors
+			if !errors.As(err, &errs) || len(errs) != 1 {
+				t.Fatalf("Struct = %v, want one validation error", err)
+			}
+			if errs[0].Namespace() != tt.failed || errs[0].Tag() != tt.tag {
+				t.Errorf("failed %s on %q, want %s on %q", errs[0].Namespace(), errs[0].Tag(), tt.failed, tt.tag)
+			}
+		})
+	}
+}
+
+func TestValidateValuer(t *testing.T) {
+	v := validator.New()
+	RegisterOptional(v)
+	if err := v.Var(optional.Of("x@example.com"), "email"); err != nil {
+		t.Errorf("Var(present) = %v", err)
+	}
+	if err := v.Var(optional.Empty[string](), "required"); err == nil {
+		t.Error("Var(empty, required) = nil, want an error")
+	}
+	if err := v.Var(optional.Empty[string](), "omitempty,email"); err != nil {
+		t.Errorf("Var(empty, omitempty) = %v", err)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/sergei-bronnikov/go-optional/optzap
+
+go 1.18
+
+require (
+	github.com/sergei-bronnikov/go-optional v0.0.0
+	go.uber.org/zap v1.28.0
+)
+
+require go.uber.org/multierr v1.10.0 // indirect
+
+replace github.com/sergei-bronnikov/go-optional => ../
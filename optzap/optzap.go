@@ -0,0 +1,104 @@
+// Package optzap logs Optionals with zap. Present values are logged with the
+// field type zap.Any picks for them, such as zap.String, zap.Int64 or
+// zap.Time, and empty Optionals are left out of the entry by default.
+//
+// Example usage:
+//
+//	logger.Info("login",
+//	    optzap.Field("user", userID),                         // "user":"u-42"
+//	    optzap.Field("referrer", referrer),                   // omitted if empty
+//	    optzap.Field("coupon", coupon, optzap.EmptyAsNull()), // "coupon":null if empty
+//	)
+package optzap
+
+import (
+	optional "github.com/sergei-bronnikov/go-optional"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Option configures how Field, AddTo and Object log an empty Optional.
+type Option func(*config)
+
+type config struct {
+	emptyAsNull bool
+}
+
+// EmptyAsNull makes an empty Optional produce a null field, for readers that
+// need to tell an empty value from a field that was never logged.
+func EmptyAsNull() Option {
+	return func(c *config) {
+		c.emptyAsNull = true
+	}
+}
+
+// Field returns a zap field for o under key. A present value is logged with
+// zap.Any, which selects the typed field for it. An empty Optional yields
+// zap.Skip(), or a null field with EmptyAsNull.
+func Field[T any](key string, o optional.Optional[T], opts ...Option) zap.Field {
+	if val, ok := o.Get(); ok {
+		return zap.Any(key, val)
+	}
+	return emptyField(key, newConfig(opts))
+}
+
+func newConfig(opts []Option) config {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+func emptyField(key string, cfg config) zap.Field {
+	if cfg.emptyAsNull {
+		return zap.Reflect(key, nil)
+	}
+	return zap.Skip()
+}
+
+// AddTo adds o to enc under key with the same rules as Field. It is meant for
+// MarshalLogObject methods of types with Optional fields, so they can be
+// logged with zap.Object.
+//
+// Example:
+//
+//	func (u User) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+//	    enc.AddString("id", u.ID)
+//	    optzap.AddTo(enc, "nickname", u.Nickname)
+//	    return nil
+//	}
+func AddTo[T any](enc zapcore.ObjectEncoder, key string, o optional.Optional[T], opts ...Option) {
+	Field(key, o, opts...).AddTo(enc)
+}
+
+// ObjectMarshaler is a zapcore.ObjectMarshaler holding one Optional under a
+// key. It lets an Optional be nested in zap.Object without writing a
+// MarshalLogObject method.
+type ObjectMarshaler[T any] struct {
+	key string
+	o   optional.Optional[T]
+	cfg config
+}
+
+// Object returns an ObjectMarshaler for o under key. Marshaling it adds o to
+// the encoder with the same rules as Field, so an empty Optional leaves an
+// empty object, or one with a null field with EmptyAsNull.
+//
+// Example:
+//
+//	logger.Info("login", zap.Object("profile", optzap.Object("nickname", nickname)))
+//	// "profile":{"nickname":"ada"}, or "profile":{} if nickname is empty
+func Object[T any](key string, o optional.Optional[T], opts ...Option) ObjectMarshaler[T] {
+	return ObjectMarshaler[T]{key: key, o: o, cfg: newConfig(opts)}
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (m ObjectMarshaler[T]) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if val, ok := m.o.Get(); ok {
+		zap.Any(m.key, val).AddTo(enc)
+		return nil
+	}
+	emptyField(m.key, m.cfg).AddTo(enc)
+	return nil
+}
@@ -0,0 +1,148 @@
+package optzap
+
+import (
+	"testing"
+	"time"
+
+	optional "github.com/sergei-bronnikov/go-optional"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// logged logs one entry with fields and returns its context as a map.
+func logged(t *testing.T, fields ...zap.Field) map[string]any {
+	t.Helper()
+	core, logs := observer.New(zap.InfoLevel)
+	zap.New(core).Info("entry", fields...)
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	return entries[0].ContextMap()
+}
+
+func TestField(t *testing.T) {
+	when := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	tests := []struct {
+		name     string
+		field    zap.Field
+		wantType zapcore.FieldType
+		want     any
+	}{
+		{"string", Field("user", optional.Of("u-42")), zapcore.StringType, "u-42"},
+		{"int", Field("n", optional.Of(42)), zapcore.Int64Type, int64(42)},
+		{"int32", Field("n", optional.Of(int32(7))), zapcore.Int32Type, int32(7)},
+		{"bool", Field("ok", optional.Of(false)), zapcore.BoolType, false},
+		{"time", Field("at", optional.Of(when)), zapcore.TimeType, when},
+		{"duration", Field("d", optional.Of(time.Second)), zapcore.DurationType, time.Second},
+		{"zero string", Field("user", optional.Of("")), zapcore.StringType, ""},
+		{"null", Field("user", optional.Empty[string](), EmptyAsNull()), zapcore.ReflectType, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.field.Type != tt.wantType {
+				t.Errorf("field type = %v, want %v", tt.field.Type, tt.wantType)
+			}
+			got, ok := logged(t, tt.field)[tt.field.Key]
+			if !ok || got != tt.want {
+				t.Errorf("logged %v (present %v), want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldEmpty(t *testing.T) {
+	f := Field("referrer", optional.Empty[string]())
+	if f.Type != zapcore.SkipType {
+		t.Errorf("field type = %v, want SkipType", f.Type)
+	}
+	if ctx := logged(t, zap.String("id", "1"), f); len(ctx) != 1 {
+		t.Errorf("logged context %v, want only id", ctx)
+	}
+}
+
+type user struct {
+	ID       string
+	Nickname optional.Optional[string]
+	Coupon   optional.Optional[int]
+}
+
+func (u user) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("id", u.ID)
+	AddTo(enc, "nickname", u.Nickname)
+	AddTo(enc, "coupon", u.Coupon, EmptyAsNull())
+	return nil
+}
+
+func TestNested(t *testing.T) {
+	tests := []struct {
+		name  string
+		field zap.Field
+		want  map[string]any
+	}{
+		{
+			"AddTo present",
+			zap.Object("user", user{ID: "1", Nickname: optional.Of("ada"), Coupon: optional.Of(5)}),
+			map[string]any{"id": "1", "nickname": "ada", "coupon": int64(5)},
+		},
+		{
+			"AddTo empty",
+			zap.Object("user", user{ID: "1"}),
+			map[string]any{"id": "1", "coupon": nil},
+		},
+		{
+			"Field of an object",
+			Field("user", optional.Of(user{ID: "2"})),
+			map[string]any{"id": "2", "coupon": nil},
+		},
+		{
+			"Object present",
+			zap.Object("profile", Object("nickname", optional.Of("ada"))),
+			map[string]any{"nickname": "ada"},
+		},
+		{
+			"Object empty",
+			zap.Object("profile", Object("nickname", optional.Empty[string]())),
+			map[string]any{},
+		},
+		{
+			"Object empty as null",
+			zap.Object("profile", Object("nickname", optional.Empty[string](), EmptyAsNull())),
+			map[string]any{"nickname": nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := logged(t, tt.field)[tt.field.Key].(map[string]any)
+			if !ok {
+				t.Fatalf("%s was not logged as an object", tt.field.Key)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("logged %v, want %v", got, tt.want)
+			}
+			for k, want := range tt.want {
+				if v, ok := got[k]; !ok || v != want {
+					t.Errorf("%s = %v (present %v), want %v", k, v, ok, want)
+				}
+			}
+		})
+	}
+}
+
+// An empty Optional is absent from, or null in, the JSON an encoder writes.
+func TestJSONEncoding(t *testing.T) {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{})
+	buf, err := enc.EncodeEntry(zapcore.Entry{}, []zap.Field{
+		Field("referrer", optional.Empty[string]()),
+		Field("coupon", optional.Empty[int](), EmptyAsNull()),
+		zap.Object("user", user{ID: "1", Nickname: optional.Of("ada")}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"coupon":null,"user":{"id":"1","nickname":"ada","coupon":null}}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("encoded %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package optional
+
+import "fmt"
+
+// OfRecover calls fn and returns an Optional containing its result, or an
+// empty Optional if fn panics. It is a containment boundary for code that
+// panics on bad input instead of returning an error, such as some third-party
+// decoders.
+//
+// Every panic is recovered, including runtime errors such as nil map writes or
+// out-of-range indexing: a boundary that lets some panics through would still
+// crash the worker it is meant to protect. Use OfRecoverErr to tell a runtime
+// error apart from a deliberate panic and re-panic if that is what you want.
+//
+// Example:
+//
+//	doc := optional.OfRecover(func() Document { return legacy.MustDecode(raw) })
+func OfRecover[T any](fn func() T) Optional[T] {
+	o, _ := OfRecoverErr(fn)
+	return o
+}
+
+// OfRecoverErr is like OfRecover, but also returns the recovered panic as an
+// error, for logging. If the panic value is an error, it is wrapped so that
+// errors.Is and errors.As see it; in particular a runtime error still matches
+// runtime.Error. Any other value is formatted with %v. The error is nil if fn
+// returns normally.
+//
+// Example:
+//
+//	doc, err := optional.OfRecoverErr(func() Document { return legacy.MustDecode(raw) })
+//	if err != nil {
+//	    log.Printf("decode: %v", err)
+//	}
+func OfRecoverErr[T any](fn func() T) (o Optional[T], err error) {
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+		// recover returns nil for panic(nil) before Go 1.21, so the
+		// completed flag rather than the recovered value detects the panic.
+		r := recover()
+		o = Empty[T]()
+		if e, ok := r.(error); ok {
+			err = fmt.Errorf("optional: recovered panic: %w", e)
+		} else {
+			err = fmt.Errorf("optional: recovered panic: %v", r)
+		}
+	}()
+	val := fn()
+	completed = true
+	return Of(val), nil
+}
@@ -0,0 +1,105 @@
+package optional
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+)
+
+func TestOfRecover(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func() int
+		want    Optional[int]
+		wantErr string
+		// is, if set, must match the returned error with errors.Is.
+		is error
+		// runtimeErr reports whether the error must match runtime.Error.
+		runtimeErr bool
+	}{
+		{
+			name: "normal return",
+			fn:   func() int { return 42 },
+			want: Of(42),
+		},
+		{
+			name: "zero return is present",
+			fn:   func() int { return 0 },
+			want: Of(0),
+		},
+		{
+			name:    "panic with error",
+			fn:      func() int { panic(io.ErrUnexpectedEOF) },
+			want:    Empty[int](),
+			wantErr: "optional: recovered panic: unexpected EOF",
+			is:      io.ErrUnexpectedEOF,
+		},
+		{
+			name:    "panic with string",
+			fn:      func() int { panic("bad input") },
+			want:    Empty[int](),
+			wantErr: "optional: recovered panic: bad input",
+		},
+		{
+			name:    "panic with int",
+			fn:      func() int { panic(7) },
+			want:    Empty[int](),
+			wantErr: "optional: recovered panic: 7",
+		},
+		{
+			name: "runtime panic",
+			fn: func() int {
+				var m map[string]int
+				m["x"] = 1
+				return 1
+			},
+			want:       Empty[int](),
+			wantErr:    "optional: recovered panic: assignment to entry in nil map",
+			runtimeErr: true,
+		},
+		{
+			name: "index out of range",
+			fn: func() int {
+				s := []int{}
+				i := 3
+				return s[i]
+			},
+			want:       Empty[int](),
+			wantErr:    "optional: recovered panic: runtime error: index out of range [3] with length 0",
+			runtimeErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OfRecover(tt.fn); got != tt.want {
+				t.Errorf("OfRecover = %v, want %v", got, tt.want)
+			}
+			got, err := OfRecoverErr(tt.fn)
+			if got != tt.want {
+				t.Errorf("OfRecoverErr = %v, want %v", got, tt.want)
+			}
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("OfRecoverErr error = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+				t.Errorf("OfRecoverErr error = %v, want %q", err, tt.wantErr)
+			}
+			if tt.is != nil && !errors.Is(err, tt.is) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.is)
+			}
+			var re runtime.Error
+			if errors.As(err, &re) != tt.runtimeErr {
+				t.Errorf("errors.As(%v, *runtime.Error) = %v, want %v", err, !tt.runtimeErr, tt.runtimeErr)
+			}
+		})
+	}
+}
+
+// panic(nil) is still a panic, whether or not recover reports it as nil.
+func TestOfRecoverPanicNil(t *testing.T) {
+	got, err := OfRecoverErr(func() int { panic(nil) })
+	if got.IsPresent() || err == nil {
+		t.Errorf("OfRecoverErr = %v, %v, want empty and an error", got, err)
+	}
+}
@@ -0,0 +1,99 @@
+package optional
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const redactedString = "Optional[REDACTED]"
+
+// Redacted wraps an Optional holding a secret, such as an API key or token, so
+// that it is not leaked through String, fmt verbs, structured logging or JSON.
+// A present value renders as "Optional[REDACTED]" and an empty one as
+// "Optional.empty"; Get still returns the real value for code that needs it.
+//
+// The zero value is an empty Redacted.
+type Redacted[T any] struct {
+	opt        Optional[T]
+	revealJSON bool
+}
+
+// Redact wraps o so that its value is masked in all textual output.
+//
+// Example:
+//
+//	token := optional.Redact(optional.Of("s3cr3t"))
+//	fmt.Println(token) // Output: Optional[REDACTED]
+func Redact[T any](o Optional[T]) Redacted[T] {
+	return Redacted[T]{opt: o}
+}
+
+// IsPresent returns true if a value is present, otherwise false.
+func (r Redacted[T]) IsPresent() bool {
+	return r.opt.IsPresent()
+}
+
+// IsEmpty returns true if no value is present, otherwise false.
+func (r Redacted[T]) IsEmpty() bool {
+	return r.opt.IsEmpty()
+}
+
+// Get returns the real, unmasked value if present, along with a boolean
+// indicating whether the value was present.
+func (r Redacted[T]) Get() (T, bool) {
+	return r.opt.Get()
+}
+
+// OrElse returns the real value if present, otherwise returns the provided default value.
+func (r Redacted[T]) OrElse(other T) T {
+	return r.opt.OrElse(other)
+}
+
+// Optional returns the wrapped, unmasked Optional.
+func (r Redacted[T]) Optional() Optional[T] {
+	return r.opt
+}
+
+// Equals reports whether both wrappers hold equal real values, using the same
+// rules as Optional.Equals.
+func (r Redacted[T]) Equals(other Redacted[T]) bool {
+	return r.opt.Equals(other.opt)
+}
+
+// String returns "Optional[REDACTED]" if a value is present, otherwise "Optional.empty".
+func (r Redacted[T]) String() string {
+	if r.opt.IsPresent() {
+		return redactedString
+	}
+	return "Optional.empty"
+}
+
+// Format implements fmt.Formatter so that every verb, including %#v and %+v,
+// renders the masked representation.
+func (r Redacted[T]) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, r.String())
+}
+
+// RevealJSON returns a copy of r whose MarshalJSON encodes the real value
+// instead of the mask. Textual output other than JSON stays masked.
+//
+// Example:
+//
+//	data, _ := json.Marshal(token.RevealJSON()) // "s3cr3t"
+func (r Redacted[T]) RevealJSON() Redacted[T] {
+	r.revealJSON = true
+	return r
+}
+
+// MarshalJSON implements json.Marshaler. An empty value is encoded as null and
+// a present value as the string "REDACTED", unless RevealJSON was used.
+func (r Redacted[T]) MarshalJSON() ([]byte, error) {
+	val, ok := r.opt.Get()
+	if !ok {
+		return []byte("null"), nil
+	}
+	if r.revealJSON {
+		return json.Marshal(val)
+	}
+	return []byte(`"REDACTED"`), nil
+}
@@ -0,0 +1,11 @@
+//go:build go1.21
+
+package optional
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer so that structured logs receive the masked
+// representation rather than the secret.
+func (r Redacted[T]) LogValue() slog.Value {
+	return slog.StringValue(r.String())
+}
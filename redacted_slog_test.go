@@ -0,0 +1,33 @@
+//go:build go1.21
+
+package optional
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedactedSlog(t *testing.T) {
+	var buf bytes.Buffer
+	text := slog.New(slog.NewTextHandler(&buf, nil))
+	text.Info("auth", "token", Redact(Of("s3cr3t")), "missing", Redact(Empty[string]()))
+	structured := slog.New(slog.NewJSONHandler(&buf, nil))
+	structured.Info("auth", slog.Any("token", Redact(Of("s3cr3t"))), slog.Group("g", "token", Redact(Of("s3cr3t"))))
+
+	out := buf.String()
+	if strings.Contains(out, "s3cr3t") {
+		t.Fatalf("secret leaked into the log:\n%s", out)
+	}
+	for _, want := range []string{
+		`token=Optional[REDACTED]`,
+		`missing=Optional.empty`,
+		`"token":"Optional[REDACTED]"`,
+		`"g":{"token":"Optional[REDACTED]"}`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output lacks %s:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package optional
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRedactedFormatting(t *testing.T) {
+	secret := Redact(Of("s3cr3t"))
+	empty := Redact(Empty[string]())
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%d", "%10v"} {
+		if got := fmt.Sprintf(verb, secret); strings.Contains(got, "s3cr3t") || !strings.Contains(got, "REDACTED") {
+			t.Errorf("Sprintf(%q) = %q, want the mask", verb, got)
+		}
+		if got := fmt.Sprintf(verb, empty); got != "Optional.empty" {
+			t.Errorf("Sprintf(%q) of empty = %q, want Optional.empty", verb, got)
+		}
+	}
+	if got := fmt.Sprint(struct{ Token Redacted[string] }{secret}); strings.Contains(got, "s3cr3t") {
+		t.Errorf("nested value leaked: %q", got)
+	}
+	if got := fmt.Sprintf("%v", &secret); strings.Contains(got, "s3cr3t") {
+		t.Errorf("pointer leaked: %q", got)
+	}
+	if got := secret.String(); got != "Optional[REDACTED]" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestRedactedGet(t *testing.T) {
+	secret := Redact(Of("s3cr3t"))
+	if v, ok := secret.Get(); v != "s3cr3t" || !ok {
+		t.Errorf("Get = %q, %v, want the real value", v, ok)
+	}
+	if got := secret.OrElse("x"); got != "s3cr3t" {
+		t.Errorf("OrElse = %q", got)
+	}
+	if !secret.Optional().Equals(Of("s3cr3t")) {
+		t.Errorf("Optional = %v", secret.Optional())
+	}
+}
+
+func TestRedactedEquals(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Redacted[string]
+		want bool
+	}{
+		{"same secret", Redact(Of("a")), Redact(Of("a")), true},
+		{"different secrets with the same mask", Redact(Of("a")), Redact(Of("b")), false},
+		{"both empty", Redact(Empty[string]()), Redacted[string]{}, true},
+		{"present vs empty", Redact(Of("")), Redact(Empty[string]()), false},
+		{"revealed vs masked", Redact(Of("a")).RevealJSON(), Redact(Of("a")), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Equals(tt.b); got != tt.want {
+				t.Errorf("Equals = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactedJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"masked", Redact(Of("s3cr3t")), `"REDACTED"`},
+		{"empty", Redact(Empty[string]()), `null`},
+		{"revealed", Redact(Of("s3cr3t")).RevealJSON(), `"s3cr3t"`},
+		{"revealed empty", Redact(Empty[string]()).RevealJSON(), `null`},
+		{"field", struct {
+			Key Redacted[int] `json:"key"`
+		}{Redact(Of(42))}, `{"key":"REDACTED"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Marshal = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package optional
+
+import "reflect"
+
+// reflective is implemented by every Optional instantiation. Its methods are
+// unexported so that no other type can satisfy it.
+type reflective interface {
+	elemType() reflect.Type
+	reflectGet() (reflect.Value, bool)
+}
+
+// reflectiveSetter is implemented by pointers to every Optional instantiation.
+type reflectiveSetter interface {
+	reflectSet(val reflect.Value)
+}
+
+var (
+	reflectiveType = reflect.TypeOf((*reflective)(nil)).Elem()
+	pkgPath        = reflect.TypeOf(Optional[int]{}).PkgPath()
+)
+
+func (o Optional[T]) elemType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (o Optional[T]) reflectGet() (reflect.Value, bool) {
+	if !o.present {
+		return reflect.Value{}, false
+	}
+	return reflect.ValueOf(&o.value).Elem(), true
+}
+
+func (o *Optional[T]) reflectSet(val reflect.Value) {
+	o.value, _ = val.Interface().(T)
+	o.present = true
+}
+
+// ElemType reports whether t is an instantiation of Optional and, if so,
+// returns its element type T. It is intended for reflection-based encoders and
+// decoders that need to recognize Optional fields.
+//
+// Example:
+//
+//	t, ok := optional.ElemType(reflect.TypeOf(optional.Of(42))) // int, true
+func ElemType(t reflect.Type) (reflect.Type, bool) {
+	// Structs embedding an Optional also implement reflective through method
+	// promotion, so the type itself must be declared in this package.
+	if t == nil || t.Kind() != reflect.Struct || t.PkgPath() != pkgPath || !t.Implements(reflectiveType) {
+		return nil, false
+	}
+	return reflect.Zero(t).Interface().(reflective).elemType(), true
+}
+
+// ReflectGet returns the value held by the Optional in v, along with a boolean
+// indicating whether the value was present. v must hold an Optional and must not
+// have been obtained through unexported struct fields; it does not need to be
+// addressable. The returned value is a copy, so changing it does not affect v.
+//
+// Example:
+//
+//	field := reflect.ValueOf(req).FieldByName("Limit")
+//	if val, ok := optional.ReflectGet(field); ok {
+//	    fmt.Println(val.Int())
+//	}
+func ReflectGet(v reflect.Value) (reflect.Value, bool) {
+	if _, ok := ElemType(v.Type()); !ok {
+		panic("optional: ReflectGet of non-Optional type " + v.Type().String())
+	}
+	return v.Interface().(reflective).reflectGet()
+}
+
+// ReflectOf returns a reflect.Value holding a present Optional of type t that
+// contains val. t must be an Optional type and val must be assignable or
+// convertible to its element type. The empty Optional of type t is simply
+// reflect.Zero(t).
+//
+// Example:
+//
+//	field := reflect.ValueOf(&req).Elem().FieldByName("Limit")
+//	field.Set(optional.ReflectOf(field.Type(), reflect.ValueOf(10)))
+func ReflectOf(t reflect.Type, val reflect.Value) reflect.Value {
+	elem, ok := ElemType(t)
+	if !ok {
+		panic("optional: ReflectOf of non-Optional type " + t.String())
+	}
+	switch {
+	case val.Type().AssignableTo(elem):
+	case val.Type().ConvertibleTo(elem):
+		val = val.Convert(elem)
+	default:
+		panic("optional: ReflectOf value of type " + val.Type().String() + " is not assignable to " + elem.String())
+	}
+	converted := reflect.New(elem).Elem()
+	converted.Set(val)
+	ptr := reflect.New(t)
+	ptr.Interface().(reflectiveSetter).reflectSet(converted)
+	return ptr.Elem()
+}
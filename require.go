@@ -0,0 +1,50 @@
+package optional
+
+import (
+	"sort"
+	"strings"
+)
+
+// Fields maps field names to values that report their presence, such as
+// pointers to Optionals of any element type. It is the argument to Require.
+type Fields map[string]interface{ IsPresent() bool }
+
+// MissingFieldsError is returned by Require when some fields are not present.
+type MissingFieldsError struct {
+	// Fields holds the names of the missing fields, sorted.
+	Fields []string
+}
+
+// Error returns a message listing every missing field, in sorted order.
+func (e *MissingFieldsError) Error() string {
+	return "optional: missing required fields: " + strings.Join(e.Fields, ", ")
+}
+
+// Require returns nil if every value in fields is present, and otherwise a
+// *MissingFieldsError naming all missing fields, so a caller can report them
+// in a single response. A nil value, including a nil *Optional, counts as
+// missing.
+//
+// Example:
+//
+//	err := optional.Require(optional.Fields{
+//	    "email": &req.Email,
+//	    "age":   &req.Age,
+//	})
+//	var missing *optional.MissingFieldsError
+//	if errors.As(err, &missing) {
+//	    return badRequest(missing.Fields)
+//	}
+func Require(fields Fields) error {
+	var missing []string
+	for name, f := range fields {
+		if f == nil || !f.IsPresent() {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	return &MissingFieldsError{Fields: missing}
+}
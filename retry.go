@@ -0,0 +1,47 @@
+package optional
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// OrElseRetry returns the value if present. Otherwise it calls fn until it
+// succeeds, returning its result, with delay between consecutive attempts. It
+// makes at most attempts calls, and at least one even if attempts is smaller.
+//
+// If every attempt fails, the last error is returned wrapped with the attempt
+// count. If ctx is done before an attempt or while waiting between attempts,
+// the context's error is returned, wrapped in the same way once fn has been
+// called; fn itself receives ctx and should observe it for long calls.
+//
+// Example:
+//
+//	rates, err := cache.Get("rates").OrElseRetry(ctx, 3, 200*time.Millisecond, fetchRates)
+func (o Optional[T]) OrElseRetry(ctx context.Context, attempts int, delay time.Duration, fn func(context.Context) (T, error)) (T, error) {
+	if o.present {
+		return o.value, nil
+	}
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	var lastErr error
+	for n := 1; ; n++ {
+		val, err := fn(ctx)
+		if err == nil {
+			return val, nil
+		}
+		lastErr = err
+		if n >= attempts {
+			return zero, fmt.Errorf("optional: gave up after attempt %d: %w", n, lastErr)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return zero, fmt.Errorf("optional: retry cancelled after attempt %d (last error: %v): %w", n, lastErr, ctx.Err())
+		}
+	}
+}
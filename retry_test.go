@@ -0,0 +1,115 @@
+package optional
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// flaky returns a supplier that fails until it has been called succeedOn
+// times, counting the calls. A succeedOn of 0 never succeeds.
+func flaky(calls *int, succeedOn int) func(context.Context) (int, error) {
+	return func(context.Context) (int, error) {
+		*calls++
+		if *calls == succeedOn {
+			return 42, nil
+		}
+		return 0, fmt.Errorf("attempt %d failed", *calls)
+	}
+}
+
+func TestOrElseRetryPresent(t *testing.T) {
+	var calls int
+	got, err := Of(7).OrElseRetry(context.Background(), 3, time.Hour, flaky(&calls, 1))
+	if got != 7 || err != nil || calls != 0 {
+		t.Errorf("OrElseRetry on present = %d, %v after %d calls, want 7, nil, 0", got, err, calls)
+	}
+}
+
+func TestOrElseRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		attempts  int
+		succeedOn int
+		want      int
+		wantCalls int
+		wantErr   string
+	}{
+		{"first try", 3, 1, 42, 1, ""},
+		{"last try", 3, 3, 42, 3, ""},
+		{"all fail", 3, 0, 0, 3, "optional: gave up after attempt 3: attempt 3 failed"},
+		{"one attempt", 1, 0, 0, 1, "optional: gave up after attempt 1: attempt 1 failed"},
+		{"zero attempts", 0, 0, 0, 1, "optional: gave up after attempt 1: attempt 1 failed"},
+		{"negative attempts", -2, 1, 42, 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			got, err := Empty[int]().OrElseRetry(context.Background(), tt.attempts, time.Microsecond, flaky(&calls, tt.succeedOn))
+			if got != tt.want || calls != tt.wantCalls {
+				t.Errorf("OrElseRetry = %d after %d calls, want %d after %d", got, calls, tt.want, tt.wantCalls)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOrElseRetryWrapsLastError(t *testing.T) {
+	errBusy := errors.New("busy")
+	var calls int
+	_, err := Empty[int]().OrElseRetry(context.Background(), 2, time.Microsecond, func(context.Context) (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, errors.New("first")
+		}
+		return 0, errBusy
+	})
+	if !errors.Is(err, errBusy) {
+		t.Errorf("error = %v, want one wrapping the last error", err)
+	}
+}
+
+func TestOrElseRetryCancelledDuringDelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int
+	fn := func(context.Context) (int, error) {
+		calls++
+		cancel()
+		return 0, errors.New("down")
+	}
+	start := time.Now()
+	_, err := Empty[int]().OrElseRetry(ctx, 5, time.Hour, fn)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if !strings.Contains(err.Error(), "after attempt 1 (last error: down)") {
+		t.Errorf("error = %q, want the attempt count and last error", err)
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times, want 1", calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Minute {
+		t.Errorf("OrElseRetry waited %v despite cancellation", elapsed)
+	}
+}
+
+func TestOrElseRetryDoneBeforeFirstAttempt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var calls int
+	_, err := Empty[int]().OrElseRetry(ctx, 3, time.Microsecond, flaky(&calls, 1))
+	if err != context.Canceled || calls != 0 {
+		t.Errorf("OrElseRetry = %v after %d calls, want the bare ctx error and no calls", err, calls)
+	}
+}
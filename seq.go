@@ -0,0 +1,51 @@
+//go:build go1.23
+
+package optional
+
+import "iter"
+
+// FirstOfSeq returns the first element of seq, or an empty Optional if seq
+// yields nothing. It stops seq after the first element, so an iterator over a
+// larger or unbounded source releases its resources without being drained.
+//
+// Example:
+//
+//	first := optional.FirstOfSeq(maps.Keys(m))
+func FirstOfSeq[T any](seq iter.Seq[T]) Optional[T] {
+	for v := range seq {
+		return Of(v)
+	}
+	return Empty[T]()
+}
+
+// FirstOfSeqFunc returns the first element of seq for which pred returns true,
+// or an empty Optional if there is none. It stops seq as soon as an element
+// matches.
+//
+// Example:
+//
+//	admin := optional.FirstOfSeqFunc(users.All(), func(u User) bool {
+//	    return u.IsAdmin
+//	})
+func FirstOfSeqFunc[T any](seq iter.Seq[T], pred func(T) bool) Optional[T] {
+	for v := range seq {
+		if pred(v) {
+			return Of(v)
+		}
+	}
+	return Empty[T]()
+}
+
+// LastOfSeq returns the last element of seq, or an empty Optional if seq
+// yields nothing. It consumes seq entirely, so seq must be finite.
+//
+// Example:
+//
+//	latest := optional.LastOfSeq(slices.Values(events))
+func LastOfSeq[T any](seq iter.Seq[T]) Optional[T] {
+	last := Empty[T]()
+	for v := range seq {
+		last = Of(v)
+	}
+	return last
+}
@@ -0,0 +1,108 @@
+//go:build go1.23
+
+package optional
+
+import (
+	"iter"
+	"testing"
+)
+
+// trackedSeq is an iterator over a resource that must be released: it counts
+// the elements pulled and whether its cleanup ran, which happens only if the
+// consumer stops it or it runs out of elements. A limit of -1 yields forever.
+type trackedSeq struct {
+	limit    int
+	pulled   int
+	released bool
+}
+
+func (s *trackedSeq) seq() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		defer func() { s.released = true }()
+		for i := 0; s.limit < 0 || i < s.limit; i++ {
+			s.pulled++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func (s *trackedSeq) check(t *testing.T, wantPulled int) {
+	t.Helper()
+	if !s.released {
+		t.Error("iterator was not released")
+	}
+	if s.pulled != wantPulled {
+		t.Errorf("pulled %d elements, want %d", s.pulled, wantPulled)
+	}
+}
+
+func TestFirstOfSeq(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		want   Optional[int]
+		pulled int
+	}{
+		{"empty", 0, Empty[int](), 0},
+		{"one", 1, Of(0), 1},
+		{"many", 100, Of(0), 1},
+		{"unbounded", -1, Of(0), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &trackedSeq{limit: tt.limit}
+			if got := FirstOfSeq(s.seq()); got != tt.want {
+				t.Errorf("FirstOfSeq = %v, want %v", got, tt.want)
+			}
+			s.check(t, tt.pulled)
+		})
+	}
+}
+
+func TestFirstOfSeqFunc(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		pred   func(int) bool
+		want   Optional[int]
+		pulled int
+	}{
+		{"empty", 0, func(int) bool { return true }, Empty[int](), 0},
+		{"first matches", 10, func(int) bool { return true }, Of(0), 1},
+		{"later match", 10, func(i int) bool { return i == 3 }, Of(3), 4},
+		{"match in unbounded", -1, func(i int) bool { return i >= 5 }, Of(5), 6},
+		{"no match drains", 10, func(int) bool { return false }, Empty[int](), 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &trackedSeq{limit: tt.limit}
+			if got := FirstOfSeqFunc(s.seq(), tt.pred); got != tt.want {
+				t.Errorf("FirstOfSeqFunc = %v, want %v", got, tt.want)
+			}
+			s.check(t, tt.pulled)
+		})
+	}
+}
+
+func TestLastOfSeq(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  Optional[int]
+	}{
+		{"empty", 0, Empty[int]()},
+		{"one", 1, Of(0)},
+		{"many", 100, Of(99)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &trackedSeq{limit: tt.limit}
+			if got := LastOfSeq(s.seq()); got != tt.want {
+				t.Errorf("LastOfSeq = %v, want %v", got, tt.want)
+			}
+			s.check(t, tt.limit)
+		})
+	}
+}
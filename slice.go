@@ -0,0 +1,41 @@
+package optional
+
+// FromPtrSlice converts a slice of pointers, such as repeated message fields
+// decoded from gRPC, into a slice of Optionals with the same length and order.
+// A nil element becomes an empty Optional; a non-nil element is copied, as in
+// OfNullable, so the result does not alias s. A nil slice yields nil.
+//
+// Example:
+//
+//	items := optional.FromPtrSlice(resp.Items) // []*Item -> []Optional[Item]
+func FromPtrSlice[T any](s []*T) []Optional[T] {
+	if s == nil {
+		return nil
+	}
+	out := make([]Optional[T], len(s))
+	for i, p := range s {
+		out[i] = OfNullable(p)
+	}
+	return out
+}
+
+// ToPtrSlice is the inverse of FromPtrSlice: an empty Optional becomes a nil
+// element, and a present one becomes a pointer to a fresh copy of its value,
+// as in Ref. Length and order are preserved, and a nil slice yields nil.
+//
+// Example:
+//
+//	resp.Items = optional.ToPtrSlice(items) // []Optional[Item] -> []*Item
+func ToPtrSlice[T any](s []Optional[T]) []*T {
+	if s == nil {
+		return nil
+	}
+	out := make([]*T, len(s))
+	for i, o := range s {
+		if o.present {
+			val := o.value
+			out[i] = &val
+		}
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package optional
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromPtrSlice(t *testing.T) {
+	a, b := 1, 2
+	in := []*int{&a, nil, &b, nil}
+	got := FromPtrSlice(in)
+	want := []Optional[int]{Of(1), Empty[int](), Of(2), Empty[int]()}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("FromPtrSlice = %v, want %v", got, want)
+	}
+
+	// The values are copied out of the pointers.
+	a = 10
+	got[2].Update(func(v *int) { *v = 20 })
+	if got[0] != Of(1) || b != 2 {
+		t.Errorf("FromPtrSlice aliases its input: result %v, input %d, %d", got, a, b)
+	}
+
+	if got := FromPtrSlice[int](nil); got != nil {
+		t.Errorf("FromPtrSlice(nil) = %#v, want nil", got)
+	}
+	if got := FromPtrSlice([]*int{}); got == nil || len(got) != 0 {
+		t.Errorf("FromPtrSlice([]) = %#v, want an empty non-nil slice", got)
+	}
+}
+
+func TestToPtrSlice(t *testing.T) {
+	in := []Optional[int]{Empty[int](), Of(1), Of(0), Empty[int]()}
+	got := ToPtrSlice(in)
+	if len(got) != len(in) || got[0] != nil || got[3] != nil || got[1] == nil || got[2] == nil {
+		t.Fatalf("ToPtrSlice = %v, want [nil, &1, &0, nil]", got)
+	}
+	if *got[1] != 1 || *got[2] != 0 {
+		t.Errorf("ToPtrSlice values = %d, %d, want 1, 0", *got[1], *got[2])
+	}
+
+	// Each pointer refers to a fresh copy, not to the Optional's storage.
+	*got[1] = 100
+	if in[1] != Of(1) {
+		t.Errorf("writing through the result changed the input to %v", in[1])
+	}
+	if got[1] == got[2] {
+		t.Error("ToPtrSlice returned the same pointer twice")
+	}
+
+	if got := ToPtrSlice[int](nil); got != nil {
+		t.Errorf("ToPtrSlice(nil) = %#v, want nil", got)
+	}
+	if got := ToPtrSlice([]Optional[int]{}); got == nil || len(got) != 0 {
+		t.Errorf("ToPtrSlice([]) = %#v, want an empty non-nil slice", got)
+	}
+}
+
+func TestPtrSliceRoundTrip(t *testing.T) {
+	type item struct{ ID int }
+	in := []*item{{ID: 1}, nil, {ID: 3}}
+	out := ToPtrSlice(FromPtrSlice(in))
+	if !reflect.DeepEqual(out, in) {
+		t.Fatalf("round trip = %v, want %v", out, in)
+	}
+	for i := range in {
+		if in[i] != nil && out[i] == in[i] {
+			t.Errorf("element %d: round trip returned the original pointer", i)
+		}
+	}
+}
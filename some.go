@@ -0,0 +1,75 @@
+package optional
+
+import "fmt"
+
+// Some is a value that is statically known to be present. Functions can take
+// a Some instead of an Optional to encode in their signature that the caller
+// has already checked for presence, so the value is read with Value and no
+// presence check or panic is needed downstream.
+//
+// A Some is obtained from an Optional with ToSome or MustSome. The zero value
+// holds the zero value of T; it is still present.
+type Some[T any] struct {
+	value T
+}
+
+// ToSome converts o into a Some, reporting false if o is empty.
+//
+// Example:
+//
+//	user, ok := optional.ToSome(repo.Find(id))
+//	if !ok {
+//	    return errNotFound
+//	}
+//	notify(user) // func notify(u optional.Some[User])
+func ToSome[T any](o Optional[T]) (Some[T], bool) {
+	if !o.present {
+		return Some[T]{}, false
+	}
+	return Some[T]{value: o.value}, true
+}
+
+// MustSome is like ToSome, but panics if o is empty. It is intended for values
+// whose presence is an invariant, such as configuration validated at startup.
+func MustSome[T any](o Optional[T]) Some[T] {
+	s, ok := ToSome(o)
+	if !ok {
+		panic(fmt.Sprintf("optional: MustSome called with an empty Optional[%s]", o.elemType()))
+	}
+	return s
+}
+
+// MapSome returns a Some holding fn applied to the value of s. The result is
+// always present, so no check is needed afterwards.
+//
+// Example:
+//
+//	email := optional.MapSome(user, func(u User) string { return u.Email })
+func MapSome[T, U any](s Some[T], fn func(T) U) Some[U] {
+	return Some[U]{value: fn(s.value)}
+}
+
+// ZipSome combines the values of a and b with fn into a Some.
+//
+// Example:
+//
+//	label := optional.ZipSome(first, last, func(f, l string) string { return f + " " + l })
+func ZipSome[A, B, C any](a Some[A], b Some[B], fn func(A, B) C) Some[C] {
+	return Some[C]{value: fn(a.value, b.value)}
+}
+
+// Value returns the value.
+func (s Some[T]) Value() T {
+	return s.value
+}
+
+// AsOptional returns a present Optional holding the value.
+func (s Some[T]) AsOptional() Optional[T] {
+	return Of(s.value)
+}
+
+// String returns a string representation of the value in the same form as
+// Optional.String, "Optional[<value>]".
+func (s Some[T]) String() string {
+	return s.AsOptional().String()
+}
@@ -0,0 +1,86 @@
+package optional
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestToSome(t *testing.T) {
+	s, ok := ToSome(Of(3))
+	if !ok || s.Value() != 3 {
+		t.Errorf("ToSome(Of(3)) = %v, %v", s, ok)
+	}
+	s, ok = ToSome(Of(0))
+	if !ok || s.Value() != 0 {
+		t.Errorf("ToSome(Of(0)) = %v, %v, want a present zero", s, ok)
+	}
+	if s, ok := ToSome(Empty[int]()); ok || s != (Some[int]{}) {
+		t.Errorf("ToSome(empty) = %v, %v, want the zero Some and false", s, ok)
+	}
+}
+
+func TestMustSome(t *testing.T) {
+	if got := MustSome(Of("x")).Value(); got != "x" {
+		t.Errorf("MustSome(Of(x)) = %q", got)
+	}
+
+	tests := []struct {
+		name string
+		call func()
+		want string
+	}{
+		{"int", func() { MustSome(Empty[int]()) }, "optional: MustSome called with an empty Optional[int]"},
+		{"error", func() { MustSome(Empty[error]()) }, "optional: MustSome called with an empty Optional[error]"},
+		{"any", func() { MustSome(Empty[any]()) }, "optional: MustSome called with an empty Optional[interface {}]"},
+		{"stringer", func() { MustSome(Empty[fmt.Stringer]()) }, "optional: MustSome called with an empty Optional[fmt.Stringer]"},
+		{"pointer", func() { MustSome(Empty[*strings.Builder]()) }, "optional: MustSome called with an empty Optional[*strings.Builder]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tt.want {
+					t.Errorf("panic = %v, want %q", r, tt.want)
+				}
+			}()
+			tt.call()
+		})
+	}
+}
+
+func TestSomeValue(t *testing.T) {
+	var zero Some[string]
+	if zero.Value() != "" || zero.AsOptional() != Of("") {
+		t.Errorf("zero Some = %q, %v, want a present empty string", zero.Value(), zero.AsOptional())
+	}
+	s := MustSome(Of(5))
+	if s.AsOptional() != Of(5) {
+		t.Errorf("AsOptional = %v, want Optional[5]", s.AsOptional())
+	}
+	if got := s.String(); got != "Optional[5]" {
+		t.Errorf("String() = %q, want Optional[5]", got)
+	}
+	if got := fmt.Sprint(zero); got != "Optional[]" {
+		t.Errorf("Sprint(zero) = %q, want Optional[]", got)
+	}
+}
+
+func TestMapSomeZipSome(t *testing.T) {
+	var calls int
+	n := MapSome(MustSome(Of("hello")), func(s string) int {
+		calls++
+		return len(s)
+	})
+	if n.Value() != 5 || calls != 1 {
+		t.Errorf("MapSome = %v after %d calls, want 5 after 1", n, calls)
+	}
+
+	errNotFound := errors.New("not found")
+	wrapped := ZipSome(MustSome(Of("user")), MustSome(Of(errNotFound)), func(what string, err error) error {
+		return fmt.Errorf("%s: %w", what, err)
+	})
+	if !errors.Is(wrapped.Value(), errNotFound) || wrapped.Value().Error() != "user: not found" {
+		t.Errorf("ZipSome = %v", wrapped.Value())
+	}
+}
@@ -0,0 +1,83 @@
+package optional
+
+import (
+	"context"
+	"sync"
+)
+
+// Waitable holds an Optional that goroutines can wait on until it becomes
+// present, such as configuration produced by an asynchronous bootstrapper. It
+// is safe for concurrent use. The zero value is an empty Waitable ready to
+// use; a Waitable must not be copied after first use.
+type Waitable[T any] struct {
+	mu    sync.Mutex
+	opt   Optional[T]
+	ready chan struct{} // closed while opt is present
+}
+
+// readyLocked returns the channel closed when a value is present.
+func (w *Waitable[T]) readyLocked() chan struct{} {
+	if w.ready == nil {
+		w.ready = make(chan struct{})
+	}
+	return w.ready
+}
+
+// Set stores v and releases every goroutine blocked in Wait.
+func (w *Waitable[T]) Set(v T) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ready := w.readyLocked()
+	if !w.opt.present {
+		close(ready)
+	}
+	w.opt = Of(v)
+}
+
+// Clear makes the Waitable empty again, so that later calls to Wait block
+// until the next Set.
+func (w *Waitable[T]) Clear() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.opt.present {
+		w.ready = make(chan struct{})
+	}
+	w.opt = Empty[T]()
+}
+
+// Load returns the current state without blocking.
+func (w *Waitable[T]) Load() Optional[T] {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.opt
+}
+
+// Wait returns the value as soon as one is present: immediately if it already
+// is, and otherwise once Set is called. It returns an empty Optional if ctx is
+// done first. A value that is set and cleared again before a waiter gets to
+// observe it does not release that waiter.
+//
+// Example:
+//
+//	cfg := config.Wait(ctx)
+//	if cfg.IsEmpty() {
+//	    return ctx.Err()
+//	}
+func (w *Waitable[T]) Wait(ctx context.Context) Optional[T] {
+	for {
+		w.mu.Lock()
+		if w.opt.present {
+			opt := w.opt
+			w.mu.Unlock()
+			return opt
+		}
+		ready := w.readyLocked()
+		w.mu.Unlock()
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return Empty[T]()
+		}
+	}
+}
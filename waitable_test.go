@@ -0,0 +1,129 @@
+package optional
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWaitableManyWaiters(t *testing.T) {
+	var w Waitable[string]
+	const waiters = 100
+	results := make(chan Optional[string], waiters)
+	var started sync.WaitGroup
+	for i := 0; i < waiters; i++ {
+		started.Add(1)
+		go func() {
+			started.Done()
+			results <- w.Wait(context.Background())
+		}()
+	}
+	started.Wait()
+	select {
+	case got := <-results:
+		t.Fatalf("Wait returned %v before Set", got)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	w.Set("cfg")
+	for i := 0; i < waiters; i++ {
+		select {
+		case got := <-results:
+			if got != Of("cfg") {
+				t.Errorf("Wait = %v, want Optional[cfg]", got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("only %d of %d waiters released", i, waiters)
+		}
+	}
+	if got := w.Wait(context.Background()); got != Of("cfg") {
+		t.Errorf("Wait after Set = %v, want Optional[cfg]", got)
+	}
+}
+
+func TestWaitableSetOverwrites(t *testing.T) {
+	var w Waitable[int]
+	w.Set(1)
+	w.Set(2)
+	if got := w.Wait(context.Background()); got != Of(2) {
+		t.Errorf("Wait = %v, want Optional[2]", got)
+	}
+	if got := w.Load(); got != Of(2) {
+		t.Errorf("Load = %v, want Optional[2]", got)
+	}
+}
+
+func TestWaitableClear(t *testing.T) {
+	var w Waitable[int]
+	w.Set(1)
+	w.Clear()
+	if got := w.Load(); got.IsPresent() {
+		t.Fatalf("Load after Clear = %v, want empty", got)
+	}
+	w.Clear()
+
+	done := make(chan Optional[int], 1)
+	go func() { done <- w.Wait(context.Background()) }()
+	select {
+	case got := <-done:
+		t.Fatalf("Wait returned %v after Clear", got)
+	case <-time.After(10 * time.Millisecond):
+	}
+	w.Set(2)
+	select {
+	case got := <-done:
+		if got != Of(2) {
+			t.Errorf("Wait = %v, want Optional[2]", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait not released by Set after Clear")
+	}
+}
+
+func TestWaitableTimeout(t *testing.T) {
+	var w Waitable[int]
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if got := w.Wait(ctx); got.IsPresent() {
+		t.Errorf("Wait = %v, want empty on timeout", got)
+	}
+	if ctx.Err() == nil {
+		t.Error("Wait returned before ctx was done")
+	}
+
+	// A value already present wins over a done ctx.
+	w.Set(3)
+	if got := w.Wait(ctx); got != Of(3) {
+		t.Errorf("Wait(done ctx) = %v, want Optional[3]", got)
+	}
+}
+
+// Set, Clear, Load and Wait run concurrently; under -race this checks the
+// locking, and every present result must be a value that was set.
+func TestWaitableConcurrent(t *testing.T) {
+	var w Waitable[int]
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				if v, ok := w.Wait(ctx).Get(); ok && v <= 0 {
+					t.Errorf("Wait = %d, never set", v)
+				}
+				w.Load()
+			}
+		}()
+	}
+	for i := 1; ctx.Err() == nil; i++ {
+		w.Set(i)
+		if i%3 == 0 {
+			w.Clear()
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,164 @@
+package optional
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// The zero value must behave exactly like Empty in every method.
+func TestZeroValueIsEmpty(t *testing.T) {
+	var zero Optional[int]
+	empty := Empty[int]()
+	never := func(int) bool {
+		t.Fatal("predicate called on an empty Optional")
+		return false
+	}
+
+	if zero.IsPresent() || !zero.IsEmpty() {
+		t.Errorf("IsPresent/IsEmpty = %v/%v, want false/true", zero.IsPresent(), zero.IsEmpty())
+	}
+	if zero.IsPresentAnd(never) {
+		t.Error("IsPresentAnd = true, want false")
+	}
+	if !zero.IsEmptyOr(never) {
+		t.Error("IsEmptyOr = false, want true")
+	}
+	if v, ok := zero.Get(); v != 0 || ok {
+		t.Errorf("Get = %v, %v, want 0, false", v, ok)
+	}
+	if got := zero.OrElse(7); got != 7 {
+		t.Errorf("OrElse = %v, want 7", got)
+	}
+	if got := zero.Inspect(func(int) { t.Error("Inspect called fn") }); got != empty {
+		t.Errorf("Inspect = %v, want empty", got)
+	}
+	zero.Update(func(*int) { t.Error("Update called fn") })
+	if zero.IsNil() {
+		t.Error("IsNil = true, want false")
+	}
+	if got := zero.OrNil(); got != nil {
+		t.Errorf("OrNil = %v, want nil", got)
+	}
+	if !zero.Equals(empty) || !empty.Equals(zero) || zero.Equals(Of(0)) {
+		t.Error("zero value does not compare equal to exactly the empty Optional")
+	}
+	if got := zero.String(); got != "Optional.empty" {
+		t.Errorf("String = %q, want Optional.empty", got)
+	}
+	if got := string(zero.AppendString(nil)); got != "Optional.empty" {
+		t.Errorf("AppendString = %q, want Optional.empty", got)
+	}
+	if got := zero.StringWith("%x"); got != "Optional.empty" {
+		t.Errorf("StringWith = %q, want Optional.empty", got)
+	}
+	if got := zero.DeepCopy(); got != empty {
+		t.Errorf("DeepCopy = %v, want empty", got)
+	}
+	out := Of(1)
+	zero.DeepCopyInto(&out)
+	if out != empty {
+		t.Errorf("DeepCopyInto left %v, want empty", out)
+	}
+	got, err := zero.OrElseRetry(context.Background(), 1, 0, func(context.Context) (int, error) { return 3, nil })
+	if got != 3 || err != nil {
+		t.Errorf("OrElseRetry = %v, %v, want 3, nil", got, err)
+	}
+}
+
+// zeroTested lists the exported methods exercised by TestZeroValueIsEmpty.
+var zeroTested = []string{
+	"AppendString", "DeepCopy", "DeepCopyInto", "Equals", "Get", "Inspect",
+	"IsEmpty", "IsEmptyOr", "IsNil", "IsPresent", "IsPresentAnd", "OrElse",
+	"OrElseRetry", "OrNil", "String", "StringWith", "Update",
+}
+
+// A method added to Optional must be added to TestZeroValueIsEmpty too.
+func TestZeroValueCoversMethodSet(t *testing.T) {
+	typ := reflect.TypeOf((*Optional[int])(nil))
+	var methods []string
+	for i := 0; i < typ.NumMethod(); i++ {
+		methods = append(methods, typ.Method(i).Name)
+	}
+	want := append([]string(nil), zeroTested...)
+	sort.Strings(want)
+	if !reflect.DeepEqual(methods, want) {
+		t.Errorf("exported methods = %v\nzero-value tests cover %v", methods, want)
+	}
+}
+
+// The presence checks and Update treat a nil *Optional as empty.
+func TestNilPointerReceiver(t *testing.T) {
+	var o *Optional[int]
+	never := func(int) bool {
+		t.Fatal("predicate called on a nil *Optional")
+		return false
+	}
+
+	if o.IsPresent() {
+		t.Error("IsPresent = true, want false")
+	}
+	if !o.IsEmpty() {
+		t.Error("IsEmpty = false, want true")
+	}
+	if o.IsPresentAnd(never) {
+		t.Error("IsPresentAnd = true, want false")
+	}
+	if !o.IsEmptyOr(never) {
+		t.Error("IsEmptyOr = false, want true")
+	}
+	o.Update(func(*int) { t.Error("Update called fn") })
+}
+
+// The other methods have value receivers, so that Optional values satisfy
+// fmt.Stringer, and calling them through a nil *Optional panics as
+// documented on the type.
+func TestNilPointerValueMethodsPanic(t *testing.T) {
+	var o *Optional[int]
+	tests := map[string]func(){
+		"String":       func() { _ = o.String() },
+		"AppendString": func() { _ = o.AppendString(nil) },
+		"Get":          func() { _, _ = o.Get() },
+		"OrElse":       func() { _ = o.OrElse(1) },
+		"Equals":       func() { _ = o.Equals(Empty[int]()) },
+	}
+	for name, call := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				err, ok := r.(error)
+				if !ok || !strings.Contains(err.Error(), "nil pointer dereference") {
+					t.Errorf("panic = %v, want a nil pointer dereference", r)
+				}
+			}()
+			call()
+		})
+	}
+}
+
+// Updating a zero Optional through a non-nil pointer leaves it empty.
+func TestZeroValueUpdateStaysEmpty(t *testing.T) {
+	var zero Optional[int]
+	p := &zero
+	p.Update(func(v *int) { *v = 5 })
+	if p.IsPresent() || zero.value != 0 {
+		t.Errorf("Update on a zero Optional = %#v, want it untouched", zero)
+	}
+}
+
+func TestRequireNilPointer(t *testing.T) {
+	var name *Optional[string]
+	age := Of(30)
+	err := Require(Fields{"name": name, "age": &age, "email": nil})
+
+	var missing *MissingFieldsError
+	if !errors.As(err, &missing) {
+		t.Fatalf("Require = %v, want a *MissingFieldsError", err)
+	}
+	if got := missing.Error(); got != "optional: missing required fields: email, name" {
+		t.Errorf("Require = %q", got)
+	}
+}